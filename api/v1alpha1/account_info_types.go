@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,6 +44,15 @@ type AccountLocation struct {
 	Path            string      `json:"path"`
 	URL             string      `json:"url"`
 	Type            AccountType `json:"type"`
+
+	// The DisplayName of the account
+	DisplayName string `json:"displayName,omitempty"`
+	// The Description of the account
+	Description *string `json:"description,omitempty"`
+	// The Creator of the account, only set for the account the AccountInfo belongs to
+	Creator *string `json:"creator,omitempty"`
+	// The Data of the account, only set for the account the AccountInfo belongs to
+	Data *apiextensionsv1.JSON `json:"data,omitempty"`
 }
 
 type FGAInfo struct {
@@ -53,14 +63,42 @@ type StoreInfo struct {
 	Id string `json:"id"`
 }
 
+const (
+	// ConditionSynced reports whether the spec reflects the account at the observed generation
+	ConditionSynced = "Synced"
+	// ConditionParentResolved reports whether the parent account and organization are known
+	ConditionParentResolved = "ParentResolved"
+	// ConditionStoreAssigned reports whether an FGA store is assigned to the organization of the account
+	ConditionStoreAssigned = "StoreAssigned"
+
+	AccountInfoReasonSynced            = "Synced"
+	AccountInfoReasonParentResolved    = "ParentResolved"
+	AccountInfoReasonOrganization      = "Organization"
+	AccountInfoReasonStoreAssigned     = "StoreAssigned"
+	AccountInfoReasonStorePending      = "StorePending"
+	AccountInfoReasonWorkspaceNotReady = "WorkspaceNotReady"
+	AccountInfoReasonParentPending     = "ParentPending"
+)
+
 // AccountInfoStatus defines the observed state of AccountInfo
 type AccountInfoStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the Account the AccountInfo was last synced from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The time of the last successful sync from the Account
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// The version of the operator that last synced the AccountInfo
+	OperatorVersion string `json:"operatorVersion,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=accountinfos
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Store Assigned",type=string,JSONPath=`.status.conditions[?(@.type=="StoreAssigned")].status`
 // AccountInfo is the Schema for the accountinfo API
 type AccountInfo struct {
 	metav1.TypeMeta   `json:",inline"`
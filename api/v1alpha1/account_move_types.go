@@ -0,0 +1,105 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccountMoveSpec defines the account to move and its new parent
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="an AccountMove can not be changed, create a new one instead"
+type AccountMoveSpec struct {
+	// Account is the name of the account to move. The account must be in the workspace of the AccountMove.
+	// +kubebuilder:validation:MinLength=1
+	Account string `json:"account"`
+
+	// TargetParentClusterID is the logical cluster of the workspace of the new parent account, as found in
+	// spec.account.generatedClusterId of the AccountInfo in that workspace. The new parent must be in the same
+	// organization as the account.
+	// +kubebuilder:validation:MinLength=1
+	TargetParentClusterID string `json:"targetParentClusterId"`
+
+	// Creator is the user that created the AccountMove, it is set by the webhook. The move is only executed if the
+	// creator may create accounts in the workspace of the new parent.
+	// +optional
+	Creator *authenticationv1.UserInfo `json:"creator,omitempty"`
+}
+
+// AccountMovePhase describes how far a move got
+type AccountMovePhase string
+
+const (
+	// AccountMovePhasePending means the move was not completed yet
+	AccountMovePhasePending AccountMovePhase = "Pending"
+	// AccountMovePhaseSucceeded means the account has the new parent
+	AccountMovePhaseSucceeded AccountMovePhase = "Succeeded"
+	// AccountMovePhaseFailed means the move was rejected, the account keeps its parent
+	AccountMovePhaseFailed AccountMovePhase = "Failed"
+)
+
+// AccountMoveStatus defines the observed state of AccountMove
+type AccountMoveStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+
+	// +kubebuilder:validation:Enum=Pending;Succeeded;Failed
+	Phase AccountMovePhase `json:"phase,omitempty"`
+	// The reason the move failed
+	Message string `json:"message,omitempty"`
+
+	// The parent account before the move
+	PreviousParent *AccountLocation `json:"previousParent,omitempty"`
+	// The time the move was completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:JSONPath=".spec.account",name="Account",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.targetParentClusterId",name="Target Parent",type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type=string
+
+// AccountMove moves an account to a different parent account within its organization.
+//
+// kcp can not move a logical cluster to a different parent workspace, so the workspace of the account keeps its path
+// and the Account stays in the workspace it was created in. The move changes the parent the account is linked to: the
+// parent recorded in its AccountInfo and the parent relation in FGA, which decides which permissions are inherited.
+type AccountMove struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountMoveSpec   `json:"spec,omitempty"`
+	Status AccountMoveStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AccountMoveList contains a list of AccountMove
+type AccountMoveList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountMove `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountMove{}, &AccountMoveList{})
+}
+
+func (i *AccountMove) GetConditions() []metav1.Condition           { return i.Status.Conditions }
+func (i *AccountMove) SetConditions(conditions []metav1.Condition) { i.Status.Conditions = conditions }
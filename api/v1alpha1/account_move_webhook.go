@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupAccountMoveWebhookWithManager registers the defaulting webhook for account moves.
+func SetupAccountMoveWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&AccountMove{}).
+		WithDefaulter(&AccountMoveDefaulter{}).
+		Complete()
+}
+
+// AccountMoveDefaulter records the creator of new account moves, a creator set by the caller is replaced.
+//
+// +kubebuilder:object:generate=false
+type AccountMoveDefaulter struct{}
+
+// Default implements admission.CustomDefaulter.
+func (a *AccountMoveDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	move := obj.(*AccountMove)
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// the spec of an existing move can not change
+	if req.Operation == admissionv1.Create {
+		move.Spec.Creator = req.UserInfo.DeepCopy()
+	}
+	return nil
+}
+
+var _ webhook.CustomDefaulter = &AccountMoveDefaulter{}
@@ -0,0 +1,28 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+func TestAccountMoveDefaulter(t *testing.T) {
+	defaulter := &v1alpha1.AccountMoveDefaulter{}
+
+	t.Run("sets the caller as creator on create", func(t *testing.T) {
+		move := &v1alpha1.AccountMove{Spec: v1alpha1.AccountMoveSpec{Creator: &authenticationv1.UserInfo{Username: "someone-else"}}}
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create, "team"), move))
+		assert.Equal(t, &authenticationv1.UserInfo{Username: "user", Groups: []string{"team"}}, move.Spec.Creator)
+	})
+
+	t.Run("keeps the creator on update", func(t *testing.T) {
+		move := &v1alpha1.AccountMove{Spec: v1alpha1.AccountMoveSpec{Creator: &authenticationv1.UserInfo{Username: "creator"}}}
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Update), move))
+		assert.Equal(t, "creator", move.Spec.Creator.Username)
+	})
+}
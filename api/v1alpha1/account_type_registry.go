@@ -0,0 +1,231 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// AccountTypeDefinition describes how accounts of a type are placed and provisioned.
+//
+// +kubebuilder:object:generate=false
+type AccountTypeDefinition struct {
+	// Name is the value of spec.type
+	Name AccountType `json:"name"`
+
+	// WorkspaceType is the kcp WorkspaceType of the account workspace
+	WorkspaceType WorkspaceTypeReference `json:"workspaceType,omitempty"`
+
+	// WorkspaceLocation selects the shard the workspaces of accounts of this type are scheduled to, unless the
+	// account sets its own location
+	WorkspaceLocation *WorkspaceLocation `json:"workspaceLocation,omitempty"`
+
+	// WorkspaceDeletionPolicy decides whether the workspaces of accounts of this type are deleted with their account,
+	// unless the account sets its own policy. Defaults to Delete.
+	WorkspaceDeletionPolicy WorkspaceDeletionPolicy `json:"workspaceDeletionPolicy,omitempty"`
+
+	// AllowedParents are the types of the accounts whose workspace may contain an account of this type. Types without
+	// allowed parents are organizations, they are created outside of accounts and start a new account hierarchy.
+	AllowedParents []AccountType `json:"allowedParents,omitempty"`
+
+	// OwnsStore is set for types whose workspace gets its own FGA store. The store is created by a workspace
+	// initializer, accounts of other types use the store of their parent.
+	OwnsStore bool `json:"ownsStore,omitempty"`
+
+	// Extensions are added to new accounts of the type, unless an extension of the same kind is already set
+	Extensions []Extension `json:"extensions,omitempty"`
+}
+
+// WorkspaceTypeReference references a kcp WorkspaceType
+//
+// +kubebuilder:object:generate=false
+type WorkspaceTypeReference struct {
+	// Name of the WorkspaceType, defaults to the name of the account type
+	Name string `json:"name,omitempty"`
+	// Path of the workspace the WorkspaceType is defined in, defaults to the provider workspace
+	Path string `json:"path,omitempty"`
+}
+
+// DefaultAccountTypes are the account types of a registry without configuration
+var DefaultAccountTypes = []AccountTypeDefinition{
+	{Name: AccountTypeOrg, OwnsStore: true},
+	{Name: AccountTypeAccount, AllowedParents: []AccountType{AccountTypeOrg, AccountTypeAccount}},
+}
+
+var defaultAccountTypeRegistry = mustNewAccountTypeRegistry(DefaultAccountTypes)
+
+// AccountTypeRegistry holds the account types by name. A nil AccountTypeRegistry holds the DefaultAccountTypes, so
+// components that are not configured with account types can be given nil.
+//
+// +kubebuilder:object:generate=false
+type AccountTypeRegistry struct {
+	types map[AccountType]AccountTypeDefinition
+	names []string
+}
+
+// NewAccountTypeRegistry validates the definitions and creates a registry from them.
+func NewAccountTypeRegistry(definitions []AccountTypeDefinition) (*AccountTypeRegistry, error) {
+	r := &AccountTypeRegistry{types: make(map[AccountType]AccountTypeDefinition, len(definitions))}
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return nil, fmt.Errorf("account types must have a name")
+		}
+		if _, ok := r.types[definition.Name]; ok {
+			return nil, fmt.Errorf("the account type %q is defined more than once", definition.Name)
+		}
+		r.types[definition.Name] = definition
+		r.names = append(r.names, string(definition.Name))
+	}
+
+	for _, definition := range definitions {
+		for _, parent := range definition.AllowedParents {
+			if _, ok := r.types[parent]; !ok {
+				return nil, fmt.Errorf("the parent %q of the account type %q is not defined", parent, definition.Name)
+			}
+		}
+		// the FGA store of an account is inherited from its parent, organizations have nothing to inherit from
+		if len(definition.AllowedParents) == 0 && !definition.OwnsStore {
+			return nil, fmt.Errorf("the account type %q has no allowed parents and must own an FGA store", definition.Name)
+		}
+		if location := definition.WorkspaceLocation; location != nil {
+			if _, err := metav1.LabelSelectorAsSelector(location.Selector); err != nil {
+				return nil, fmt.Errorf("the workspace location of the account type %q is invalid: %w", definition.Name, err)
+			}
+		}
+		switch definition.WorkspaceDeletionPolicy {
+		case "", WorkspaceDeletionPolicyDelete, WorkspaceDeletionPolicyRetain:
+		default:
+			return nil, fmt.Errorf("the workspace deletion policy %q of the account type %q is invalid, supported policies are %s, %s",
+				definition.WorkspaceDeletionPolicy, definition.Name, WorkspaceDeletionPolicyDelete, WorkspaceDeletionPolicyRetain)
+		}
+	}
+	return r, nil
+}
+
+func mustNewAccountTypeRegistry(definitions []AccountTypeDefinition) *AccountTypeRegistry {
+	r, err := NewAccountTypeRegistry(definitions)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// LoadAccountTypesFile reads a yaml or json list of account type definitions.
+func LoadAccountTypesFile(path string) (*AccountTypeRegistry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []AccountTypeDefinition
+	if err := yaml.UnmarshalStrict(raw, &definitions); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return NewAccountTypeRegistry(definitions)
+}
+
+// registry resolves a nil registry to the DefaultAccountTypes, all methods look up the account types through it.
+func (r *AccountTypeRegistry) registry() *AccountTypeRegistry {
+	if r == nil {
+		return defaultAccountTypeRegistry
+	}
+	return r
+}
+
+// Get returns the definition of the account type.
+func (r *AccountTypeRegistry) Get(accountType AccountType) (AccountTypeDefinition, bool) {
+	definition, ok := r.registry().types[accountType]
+	return definition, ok
+}
+
+// Names returns the names of all account types in the order they were defined.
+func (r *AccountTypeRegistry) Names() []string {
+	return slices.Clone(r.registry().names)
+}
+
+// IsOrganization reports whether accounts of the type start a new account hierarchy.
+func (r *AccountTypeRegistry) IsOrganization(accountType AccountType) bool {
+	definition, ok := r.Get(accountType)
+	return ok && len(definition.AllowedParents) == 0
+}
+
+// OwnsStore reports whether the workspace of accounts of the type gets its own FGA store.
+func (r *AccountTypeRegistry) OwnsStore(accountType AccountType) bool {
+	definition, _ := r.Get(accountType)
+	return definition.OwnsStore
+}
+
+// WorkspaceType returns the name and path of the kcp WorkspaceType of the account type. defaultPath is used for types
+// that do not set a path.
+func (r *AccountTypeRegistry) WorkspaceType(accountType AccountType, defaultPath string) (string, string) {
+	definition, _ := r.Get(accountType)
+	name, path := definition.WorkspaceType.Name, definition.WorkspaceType.Path
+	if name == "" {
+		name = string(accountType)
+	}
+	if path == "" {
+		path = defaultPath
+	}
+	return name, path
+}
+
+// WorkspaceLocation returns the location of the workspace of an account. The location of the account wins over the
+// location of its type, defaultLocation is used if neither is set.
+func (r *AccountTypeRegistry) WorkspaceLocation(account *Account, defaultLocation *WorkspaceLocation) *WorkspaceLocation {
+	if account.Spec.WorkspaceLocation != nil {
+		return account.Spec.WorkspaceLocation
+	}
+	if definition, _ := r.Get(account.Spec.Type); definition.WorkspaceLocation != nil {
+		return definition.WorkspaceLocation
+	}
+	return defaultLocation
+}
+
+// WorkspaceDeletionPolicy returns the deletion policy of the workspace of an account. The policy of the account wins
+// over the policy of its type, workspaces are deleted if neither is set.
+func (r *AccountTypeRegistry) WorkspaceDeletionPolicy(account *Account) WorkspaceDeletionPolicy {
+	if account.Spec.WorkspaceDeletionPolicy != "" {
+		return account.Spec.WorkspaceDeletionPolicy
+	}
+	if definition, _ := r.Get(account.Spec.Type); definition.WorkspaceDeletionPolicy != "" {
+		return definition.WorkspaceDeletionPolicy
+	}
+	return WorkspaceDeletionPolicyDelete
+}
+
+// ValidatePlacement checks whether an account of the given type may be created in a workspace. parent is the
+// AccountInfo of that workspace, nil if the workspace does not belong to an account, like the provider workspace.
+// Organizations may only be created outside of accounts, other types only inside accounts of their allowed parent types.
+func (r *AccountTypeRegistry) ValidatePlacement(accountType AccountType, parent *AccountInfo) error {
+	definition, ok := r.Get(accountType)
+	switch {
+	case !ok:
+		return fmt.Errorf("the account type %q is not defined, supported types are %s", accountType, strings.Join(r.Names(), ", "))
+	case parent == nil && len(definition.AllowedParents) > 0:
+		parents := make([]string, len(definition.AllowedParents))
+		for i, parentType := range definition.AllowedParents {
+			parents[i] = string(parentType)
+		}
+		return fmt.Errorf("%s %s can only be created inside %s %s workspace", article(accountType), accountType, article(definition.AllowedParents[0]), joinOr(parents))
+	case parent != nil && !slices.Contains(definition.AllowedParents, parent.Spec.Account.Type):
+		return fmt.Errorf("%s %s can not be created inside the %s workspace %q", article(accountType), accountType, parent.Spec.Account.Type, parent.Spec.Account.Path)
+	}
+	return nil
+}
+
+func article(accountType AccountType) string {
+	if strings.ContainsAny(string(accountType)[:1], "aeiouAEIOU") {
+		return "an"
+	}
+	return "a"
+}
+
+func joinOr(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
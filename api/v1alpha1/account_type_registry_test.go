@@ -0,0 +1,143 @@
+package v1alpha1_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+const accountTypes = `
+- name: org
+  ownsStore: true
+- name: account
+  allowedParents: [org, account]
+- name: project
+  workspaceType:
+    name: project-workspace
+    path: root:types
+  workspaceLocation:
+    selector:
+      matchLabels:
+        shard: projects
+  workspaceDeletionPolicy: Retain
+  allowedParents: [account]
+  extensions:
+  - apiVersion: example.openmfp.org/v1alpha1
+    kind: Budget
+    specGoTemplate: {}
+`
+
+func TestValidatePlacement(t *testing.T) {
+	var types *v1alpha1.AccountTypeRegistry
+	org := &v1alpha1.AccountInfo{Spec: v1alpha1.AccountInfoSpec{Account: v1alpha1.AccountLocation{Type: v1alpha1.AccountTypeOrg, Path: "root:orgs:org"}}}
+	account := &v1alpha1.AccountInfo{Spec: v1alpha1.AccountInfoSpec{Account: v1alpha1.AccountLocation{Type: v1alpha1.AccountTypeAccount, Path: "root:orgs:org:account"}}}
+
+	assert.NoError(t, types.ValidatePlacement(v1alpha1.AccountTypeOrg, nil))
+	assert.EqualError(t, types.ValidatePlacement(v1alpha1.AccountTypeOrg, org), `an org can not be created inside the org workspace "root:orgs:org"`)
+	assert.EqualError(t, types.ValidatePlacement(v1alpha1.AccountTypeOrg, account), `an org can not be created inside the account workspace "root:orgs:org:account"`)
+
+	assert.EqualError(t, types.ValidatePlacement(v1alpha1.AccountTypeAccount, nil), "an account can only be created inside an org or account workspace")
+	assert.NoError(t, types.ValidatePlacement(v1alpha1.AccountTypeAccount, org))
+	assert.NoError(t, types.ValidatePlacement(v1alpha1.AccountTypeAccount, account))
+
+	assert.EqualError(t, types.ValidatePlacement("project", account), `the account type "project" is not defined, supported types are org, account`)
+}
+
+func TestLoadAccountTypesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(accountTypes), 0o600))
+
+	types, err := v1alpha1.LoadAccountTypesFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org", "account", "project"}, types.Names())
+
+	assert.True(t, types.IsOrganization(v1alpha1.AccountTypeOrg))
+	assert.False(t, types.IsOrganization("project"))
+	assert.True(t, types.OwnsStore(v1alpha1.AccountTypeOrg))
+	assert.False(t, types.OwnsStore("project"))
+
+	name, workspacePath := types.WorkspaceType("project", "root")
+	assert.Equal(t, "project-workspace", name)
+	assert.Equal(t, "root:types", workspacePath)
+	name, workspacePath = types.WorkspaceType(v1alpha1.AccountTypeAccount, "root")
+	assert.Equal(t, "account", name)
+	assert.Equal(t, "root", workspacePath)
+
+	defaultLocation := &v1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "default"}}}
+	projectAccount := &v1alpha1.Account{Spec: v1alpha1.AccountSpec{Type: "project"}}
+	assert.Equal(t, map[string]string{"shard": "projects"}, types.WorkspaceLocation(projectAccount, defaultLocation).Selector.MatchLabels)
+	projectAccount.Spec.WorkspaceLocation = &v1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "dedicated"}}}
+	assert.Equal(t, map[string]string{"shard": "dedicated"}, types.WorkspaceLocation(projectAccount, defaultLocation).Selector.MatchLabels)
+	accountAccount := &v1alpha1.Account{Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount}}
+	assert.Equal(t, defaultLocation, types.WorkspaceLocation(accountAccount, defaultLocation))
+
+	assert.Equal(t, v1alpha1.WorkspaceDeletionPolicyRetain, types.WorkspaceDeletionPolicy(projectAccount))
+	projectAccount.Spec.WorkspaceDeletionPolicy = v1alpha1.WorkspaceDeletionPolicyDelete
+	assert.Equal(t, v1alpha1.WorkspaceDeletionPolicyDelete, types.WorkspaceDeletionPolicy(projectAccount))
+	assert.Equal(t, v1alpha1.WorkspaceDeletionPolicyDelete, types.WorkspaceDeletionPolicy(accountAccount))
+
+	project, ok := types.Get("project")
+	require.True(t, ok)
+	require.Len(t, project.Extensions, 1)
+	assert.Equal(t, "Budget", project.Extensions[0].Kind)
+
+	org := &v1alpha1.AccountInfo{Spec: v1alpha1.AccountInfoSpec{Account: v1alpha1.AccountLocation{Type: v1alpha1.AccountTypeOrg, Path: "root:orgs:org"}}}
+	account := &v1alpha1.AccountInfo{Spec: v1alpha1.AccountInfoSpec{Account: v1alpha1.AccountLocation{Type: v1alpha1.AccountTypeAccount, Path: "root:orgs:org:account"}}}
+	assert.NoError(t, types.ValidatePlacement("project", account))
+	assert.EqualError(t, types.ValidatePlacement("project", org), `a project can not be created inside the org workspace "root:orgs:org"`)
+	assert.EqualError(t, types.ValidatePlacement("project", nil), "a project can only be created inside an account workspace")
+}
+
+func TestNewAccountTypeRegistry_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []v1alpha1.AccountTypeDefinition
+		err         string
+	}{
+		{
+			name:        "missing name",
+			definitions: []v1alpha1.AccountTypeDefinition{{OwnsStore: true}},
+			err:         "account types must have a name",
+		},
+		{
+			name:        "duplicate",
+			definitions: []v1alpha1.AccountTypeDefinition{{Name: "org", OwnsStore: true}, {Name: "org", OwnsStore: true}},
+			err:         `the account type "org" is defined more than once`,
+		},
+		{
+			name:        "unknown parent",
+			definitions: []v1alpha1.AccountTypeDefinition{{Name: "account", AllowedParents: []v1alpha1.AccountType{"org"}}},
+			err:         `the parent "org" of the account type "account" is not defined`,
+		},
+		{
+			name: "invalid workspace location",
+			definitions: []v1alpha1.AccountTypeDefinition{{Name: "org", OwnsStore: true, WorkspaceLocation: &v1alpha1.WorkspaceLocation{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "shard", Operator: "Near"}}},
+			}}},
+			err: `the workspace location of the account type "org" is invalid: "Near" is not a valid label selector operator`,
+		},
+		{
+			name:        "invalid workspace deletion policy",
+			definitions: []v1alpha1.AccountTypeDefinition{{Name: "org", OwnsStore: true, WorkspaceDeletionPolicy: "Orphan"}},
+			err:         `the workspace deletion policy "Orphan" of the account type "org" is invalid, supported policies are Delete, Retain`,
+		},
+		{
+			name:        "organization without store",
+			definitions: []v1alpha1.AccountTypeDefinition{{Name: "org"}},
+			err:         `the account type "org" has no allowed parents and must own an FGA store`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := v1alpha1.NewAccountTypeRegistry(test.definitions)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
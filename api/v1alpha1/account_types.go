@@ -32,8 +32,8 @@ const (
 
 // AccountSpec defines the desired state of Account
 type AccountSpec struct {
-	// Type specifies the intended type for this Account object.
-	// +kubebuilder:validation:Enum=org;account
+	// Type specifies the intended type for this Account object. The supported types are configured in the operator,
+	// by default org and account.
 	Type AccountType `json:"type"`
 
 	// The display name for this account
@@ -50,17 +50,220 @@ type AccountSpec struct {
 
 	// Additional information that should be stored with the account
 	Data *apiextensionsv1.JSON `json:"data,omitempty"`
+
+	// DeletionProtection denies the deletion of the account until it is set to false again
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// Suspended disables the account without deleting it. The creator and owner tuples of the account are removed
+	// from FGA while the account is suspended and written again once it is resumed.
+	Suspended bool `json:"suspended,omitempty"`
+
+	// WorkspaceLocation selects the shard the account workspace is scheduled to, it overrides the location of the
+	// account type and can not be changed once the account exists
+	WorkspaceLocation *WorkspaceLocation `json:"workspaceLocation,omitempty"`
+
+	// WorkspaceDeletionPolicy decides whether the account workspace is deleted or kept when the account is deleted,
+	// it overrides the policy of the account type
+	// +kubebuilder:validation:Enum=Delete;Retain
+	WorkspaceDeletionPolicy WorkspaceDeletionPolicy `json:"workspaceDeletionPolicy,omitempty"`
 }
 
+// WorkspaceLocation is the location of a kcp workspace
+type WorkspaceLocation struct {
+	// Selector filters the shards the workspace can be scheduled to by their labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// WorkspaceDeletionPolicy describes what happens to the account workspace when its account is deleted
+type WorkspaceDeletionPolicy string
+
+const (
+	// WorkspaceDeletionPolicyDelete deletes the workspace before the account is removed
+	WorkspaceDeletionPolicyDelete WorkspaceDeletionPolicy = "Delete"
+	// WorkspaceDeletionPolicyRetain keeps the workspace, it is detached from the account and labeled as orphaned
+	WorkspaceDeletionPolicyRetain WorkspaceDeletionPolicy = "Retain"
+)
+
+// ExtensionDeletionPolicy describes what happens to an extension object when its account is deleted
+type ExtensionDeletionPolicy string
+
+const (
+	// ExtensionDeletionPolicyDelete removes the extension object before the account is removed
+	ExtensionDeletionPolicyDelete ExtensionDeletionPolicy = "Delete"
+	// ExtensionDeletionPolicyOrphan keeps the extension object when the account is removed
+	ExtensionDeletionPolicyOrphan ExtensionDeletionPolicy = "Orphan"
+)
+
 type Extension struct {
-	metav1.TypeMeta    `json:",inline"`
+	metav1.TypeMeta `json:",inline"`
+	// MetadataGoTemplate is rendered into the metadata of the extension object. String values are go templates,
+	// see the templating package for the available data and functions.
 	MetadataGoTemplate apiextensionsv1.JSON `json:"metadataGoTemplate,omitempty"`
-	SpecGoTemplate     apiextensionsv1.JSON `json:"specGoTemplate"`
+	// SpecGoTemplate is rendered into the spec of the extension object, like MetadataGoTemplate.
+	SpecGoTemplate apiextensionsv1.JSON `json:"specGoTemplate"`
 
 	// The type of a condition that must be set to True on the Extension object
 	// for the extension to be considered reconciled and ready. If this is empty,
 	// the extension is considered ready.
 	ReadyConditionType *string `json:"readyConditionType,omitempty"`
+
+	// DeletionPolicy decides whether the extension object is deleted or kept when the account is deleted.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +kubebuilder:default=Delete
+	DeletionPolicy ExtensionDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// CreateOnly creates the extension object once and leaves it untouched afterwards, for objects that users are
+	// expected to change after creation. Drift is not repaired for create-only extensions.
+	CreateOnly bool `json:"createOnly,omitempty"`
+}
+
+// ExtensionPhase describes how far an extension object got in its lifecycle
+type ExtensionPhase string
+
+const (
+	// ExtensionPhasePending means the extension object was not applied yet
+	ExtensionPhasePending ExtensionPhase = "Pending"
+	// ExtensionPhaseApplied means the extension object exists but its ready condition is not True yet
+	ExtensionPhaseApplied ExtensionPhase = "Applied"
+	// ExtensionPhaseReady means the extension object exists and is ready
+	ExtensionPhaseReady ExtensionPhase = "Ready"
+	// ExtensionPhaseFailed means the extension could not be rendered or applied
+	ExtensionPhaseFailed ExtensionPhase = "Failed"
+	// ExtensionPhaseRemoved means the extension object is no longer rendered by the extensions of the account, it is
+	// deleted according to its deletion policy
+	ExtensionPhaseRemoved ExtensionPhase = "Removed"
+)
+
+const (
+	// ConditionReady is the overall condition of an account, set by the reconciliation lifecycle
+	ConditionReady = "Ready"
+
+	// ConditionExtensionsReady summarizes the state of all extensions of an account
+	ConditionExtensionsReady = "ExtensionsReady"
+
+	ExtensionReasonReady             = "ExtensionsReady"
+	ExtensionReasonWorkspaceNotReady = "WorkspaceNotReady"
+	ExtensionReasonRenderFailed      = "ExtensionRenderFailed"
+	ExtensionReasonApplyFailed       = "ExtensionApplyFailed"
+	ExtensionReasonNotReady          = "ExtensionNotReady"
+)
+
+const (
+	// ConditionDataValid reports whether spec.data matches the schema registered for the account type
+	ConditionDataValid = "DataValid"
+
+	DataReasonValid   = "DataValid"
+	DataReasonInvalid = "DataInvalid"
+)
+
+const (
+	// ConditionHierarchyDepthValid reports whether the account is nested within the maximum depth of its organization
+	ConditionHierarchyDepthValid = "HierarchyDepthValid"
+
+	HierarchyReasonDepthWithinLimit = "DepthWithinLimit"
+	HierarchyReasonDepthExceeded    = "DepthExceeded"
+)
+
+const (
+	// ConditionWorkspaceReady reports whether the account workspace is ready, and whether it is still provisioning or
+	// exceeded the provisioning timeout if not
+	ConditionWorkspaceReady = "WorkspaceReady"
+
+	WorkspaceReasonReady               = "WorkspaceReady"
+	WorkspaceReasonProvisioning        = "WorkspaceProvisioning"
+	WorkspaceReasonProvisioningTimeout = "ProvisioningTimeout"
+	WorkspaceReasonConflict            = "WorkspaceConflict"
+)
+
+const (
+	// ConditionSuspended reports on an account whether its access is revoked because it is suspended, on an
+	// AccountInfo whether the account of the workspace is suspended
+	ConditionSuspended = "Suspended"
+
+	SuspensionReasonSuspended = "Suspended"
+	SuspensionReasonActive    = "Active"
+)
+
+const (
+	// ConditionChildAccountsDeleted reports whether the accounts in the workspace of a deleted account are gone, it
+	// is only set when cascading deletion is enabled
+	ConditionChildAccountsDeleted = "ChildAccountsDeleted"
+
+	ChildAccountsReasonDeleted   = "ChildAccountsDeleted"
+	ChildAccountsReasonPending   = "ChildAccountsPending"
+	ChildAccountsReasonProtected = "ChildAccountsProtected"
+)
+
+// AccountPhase describes the lifecycle stage of an account
+type AccountPhase string
+
+const (
+	// AccountPhaseDeleted means the account was deleted and is kept until status.restorableUntil, it can be
+	// restored with the restore annotation until then
+	AccountPhaseDeleted AccountPhase = "Deleted"
+	// AccountPhaseSuspended means the access granted to the creator of the account is revoked until spec.suspended
+	// is unset
+	AccountPhaseSuspended AccountPhase = "Suspended"
+	// AccountPhaseFailed means the account workspace did not become ready within the provisioning timeout
+	AccountPhaseFailed AccountPhase = "Failed"
+)
+
+// RestoreAnnotation set to "true" on an account in the Deleted phase brings the account back
+const RestoreAnnotation = "core.openmfp.org/restore"
+
+// OrphanedWorkspaceLabel is set to "true" on the workspaces retained after the deletion of their account
+const OrphanedWorkspaceLabel = "core.openmfp.org/orphaned"
+
+// AdoptAnnotation on an existing workspace names the account in the same workspace that may adopt it
+const AdoptAnnotation = "core.openmfp.org/adopt-by-account"
+
+// RestoredAccountAnnotation on a workspace holds the account that is restored into it as JSON, until the account adopted
+// the workspace. It is written before the deleted account is released, so the account can be created again if the
+// restore is interrupted.
+const RestoredAccountAnnotation = "core.openmfp.org/restored-account"
+
+// ExtensionStatus reports the observed state of the object rendered from a single extension
+type ExtensionStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The name of the rendered extension object
+	Name string `json:"name,omitempty"`
+	// The namespace of the rendered extension object, empty for cluster scoped objects
+	Namespace string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:Enum=Pending;Applied;Ready;Failed;Removed
+	Phase ExtensionPhase `json:"phase"`
+
+	// The deletion policy of the extension the object was applied for
+	DeletionPolicy ExtensionDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// The last error that occurred while rendering or applying the extension
+	LastError string `json:"lastError,omitempty"`
+
+	// The ready condition as observed on the extension object
+	ReadyCondition *ObservedCondition `json:"readyCondition,omitempty"`
+
+	// The last time a manual change of a managed field was detected and reverted
+	LastDriftCorrectionTime *metav1.Time `json:"lastDriftCorrectionTime,omitempty"`
+}
+
+// ObservedCondition is a condition copied from an object that is not managed by this operator
+type ObservedCondition struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// AccountWorkspaceStatus reports the observed state of the kcp workspace of an account
+type AccountWorkspaceStatus struct {
+	// The phase of the workspace, like Scheduling, Initializing or Ready
+	Phase string `json:"phase,omitempty"`
+	// The logical cluster of the workspace
+	ClusterID string `json:"clusterId,omitempty"`
+	// The URL of the workspace
+	URL string `json:"url,omitempty"`
 }
 
 // AccountStatus defines the observed state of Account
@@ -68,14 +271,30 @@ type AccountStatus struct {
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ObservedGeneration int64              `json:"observedGeneration,omitempty" protobuf:"varint,3,opt,name=observedGeneration"`
 	NextReconcileTime  metav1.Time        `json:"nextReconcileTime,omitempty"`
+
+	// The state of the objects rendered from the account extensions, in declaration order, followed by the objects of
+	// removed extensions that are not deleted yet
+	Extensions []ExtensionStatus `json:"extensions,omitempty"`
+
+	// The observed state of the account workspace
+	Workspace *AccountWorkspaceStatus `json:"workspace,omitempty"`
+
+	// The lifecycle stage of the account, empty for active accounts
+	// +kubebuilder:validation:Enum=Deleted;Suspended;Failed
+	Phase AccountPhase `json:"phase,omitempty"`
+
+	// The time until which a deleted account can be restored
+	RestorableUntil *metav1.Time `json:"restorableUntil,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:JSONPath=".spec.displayName",name="Display Name",type=string
 // +kubebuilder:printcolumn:JSONPath=".spec.type",name="Type",type=string
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type=string
 
 // Account is the Schema for the accounts API
 type Account struct {
@@ -2,21 +2,54 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmfp/account-operator/pkg/dataschema"
+	"github.com/openmfp/account-operator/pkg/templating"
 )
 
-func SetupAccountWebhookWithManager(mgr ctrl.Manager) error {
+// SetupAccountWebhookWithManager registers the defaulting and validating webhook for accounts. The client of the
+// validator is set to the client of the manager.
+func SetupAccountWebhookWithManager(mgr ctrl.Manager, defaulter *AccountDefaulter, validator *AccountValidator) error {
+	validator.Client = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&Account{}).
-		WithDefaulter(&AccountDefaulter{}).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
 		Complete()
 }
 
-type AccountDefaulter struct{}
+// AccountDefaulter records the creator of new accounts and adds the default extensions of their type.
+//
+// +kubebuilder:object:generate=false
+type AccountDefaulter struct {
+	// AccountTypes defines the default extensions per account type
+	AccountTypes *AccountTypeRegistry
+	// CreatorAuthorizer decides whether a caller may set the creator of a new account. If nil, the creator of a new
+	// account is always the caller.
+	CreatorAuthorizer *CreatorAuthorizer
+}
 
 // Default implements admission.CustomDefaulter.
 func (a *AccountDefaulter) Default(ctx context.Context, obj runtime.Object) error {
@@ -27,9 +60,343 @@ func (a *AccountDefaulter) Default(ctx context.Context, obj runtime.Object) erro
 		return err
 	}
 
-	account.Spec.Creator = &req.UserInfo.Username
+	switch req.Operation {
+	case admissionv1.Create:
+		creator, err := a.creator(ctx, account, req.UserInfo)
+		if err != nil {
+			return err
+		}
+		account.Spec.Creator = &creator
+		a.addDefaultExtensions(account)
+	case admissionv1.Update:
+		// the creator is kept if an update does not carry it, changing it is up to the validator
+		if account.Spec.Creator == nil && len(req.OldObject.Raw) > 0 {
+			old := &Account{}
+			if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+				return err
+			}
+			account.Spec.Creator = old.Spec.Creator
+		}
+	}
 
 	return nil
 }
 
+// creator returns the creator of a new account. A creator set on the account is kept if the caller is allowed to
+// override it, otherwise the caller is the creator.
+func (a *AccountDefaulter) creator(ctx context.Context, account *Account, user authenticationv1.UserInfo) (string, error) {
+	requested := account.Spec.Creator
+	if requested == nil || *requested == "" || *requested == user.Username {
+		return user.Username, nil
+	}
+
+	allowed, err := a.CreatorAuthorizer.Allowed(ctx, account, user)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return user.Username, nil
+	}
+	return *requested, nil
+}
+
+// CreatorAuthorizer checks with a SubjectAccessReview whether a caller may set the creator of a new account to someone
+// else.
+//
+// +kubebuilder:object:generate=false
+type CreatorAuthorizer struct {
+	// Client creates the SubjectAccessReviews. If nil, only the operator may set the creator.
+	Client client.Client
+	// Permission is the permission a caller needs in the workspace of the account to set its creator
+	Permission authorizationv1.ResourceAttributes
+	// Operator is the user name of the operator. The operator recreates restored accounts with their original creator
+	// and is always allowed to set it.
+	Operator string
+}
+
+// Allowed reviews the permission of the caller in the workspace of the account. A nil authorizer allows nobody.
+func (a *CreatorAuthorizer) Allowed(ctx context.Context, account *Account, user authenticationv1.UserInfo) (bool, error) {
+	if a == nil {
+		return false, nil
+	}
+	if a.Operator != "" && user.Username == a.Operator {
+		return true, nil
+	}
+	if a.Client == nil {
+		return false, nil
+	}
+
+	attributes := a.Permission
+	attributes.Name = account.Name
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &attributes,
+		User:               user.Username,
+		UID:                user.UID,
+		Groups:             user.Groups,
+	}}
+	if len(user.Extra) > 0 {
+		review.Spec.Extra = make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			review.Spec.Extra[key] = authorizationv1.ExtraValue(value)
+		}
+	}
+	// permissions are granted per workspace, the review is made in the workspace of the account
+	if cluster := account.GetAnnotations()[logicalcluster.AnnotationKey]; cluster != "" {
+		ctx = kontext.WithCluster(ctx, logicalcluster.Name(cluster))
+	}
+	if err := a.Client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// addDefaultExtensions appends the extensions of the account type whose kind is not set on the account yet
+func (a *AccountDefaulter) addDefaultExtensions(account *Account) {
+	definition, _ := a.AccountTypes.Get(account.Spec.Type)
+	for _, extension := range definition.Extensions {
+		exists := slices.ContainsFunc(account.Spec.Extensions, func(existing Extension) bool {
+			return existing.GroupVersionKind() == extension.GroupVersionKind()
+		})
+		if !exists {
+			account.Spec.Extensions = append(account.Spec.Extensions, *extension.DeepCopy())
+		}
+	}
+}
+
 var _ webhook.CustomDefaulter = &AccountDefaulter{}
+
+// AccountValidator rejects accounts with an invalid spec, changes of immutable fields and accounts that are created in
+// a workspace their type is not allowed in or nested deeper than allowed.
+//
+// +kubebuilder:object:generate=false
+type AccountValidator struct {
+	// Client reads the AccountInfo of the workspace an account is created in
+	Client client.Client
+	// DataSchemas validate spec.data per account type
+	DataSchemas *dataschema.Registry
+	// DepthLimits is the maximum depth of new accounts
+	DepthLimits DepthLimits
+	// ChildAccountLimits is the number of accounts per workspace
+	ChildAccountLimits ChildAccountLimits
+	// AccountTypes defines the supported account types and where they may be created
+	AccountTypes *AccountTypeRegistry
+	// ProtectAccountsWithChildren denies the deletion of accounts whose workspace still contains accounts
+	ProtectAccountsWithChildren bool
+}
+
+// ValidateCreate implements admission.CustomValidator.
+func (v *AccountValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	account := obj.(*Account)
+
+	errs := v.validateAccountSpec(account, nil)
+
+	// the workspace is checked if it is known, the checks are repeated before the account workspace is created
+	cluster := account.GetAnnotations()[logicalcluster.AnnotationKey]
+	if v.Client == nil || cluster == "" {
+		return nil, toInvalidError(account, errs)
+	}
+	ctx = kontext.WithCluster(ctx, logicalcluster.Name(cluster))
+
+	parent, err := v.getAccountInfo(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	hierarchyErr, err := v.validateHierarchy(ctx, account, parent)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if hierarchyErr != nil {
+		errs = append(errs, hierarchyErr)
+	}
+	if len(errs) > 0 {
+		return nil, toInvalidError(account, errs)
+	}
+
+	return nil, v.validateQuota(ctx, account, parent)
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (v *AccountValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAccount := oldObj.(*Account)
+	account := newObj.(*Account)
+
+	// the finalizers of an account that is being deleted must always be removable
+	if account.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+	errs := v.validateAccountSpec(account, oldAccount)
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(account.Spec.Type, oldAccount.Spec.Type, specPath.Child("type"))...)
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(account.Spec.WorkspaceLocation, oldAccount.Spec.WorkspaceLocation, specPath.Child("workspaceLocation"))...)
+	// the FGA tuples of the creator are only written once, the creator is therefore fixed at creation
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(account.Spec.Creator, oldAccount.Spec.Creator, specPath.Child("creator"))...)
+
+	return nil, toInvalidError(account, errs)
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (v *AccountValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	account := obj.(*Account)
+	accounts := GroupVersion.WithResource("accounts").GroupResource()
+
+	if account.Spec.DeletionProtection {
+		return nil, apierrors.NewForbidden(accounts, account.Name,
+			fmt.Errorf("the account is protected from deletion, set spec.deletionProtection to false to delete it"))
+	}
+
+	if v.ProtectAccountsWithChildren && v.Client != nil {
+		children, err := v.countChildAccounts(ctx, account)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		if children > 0 {
+			return nil, apierrors.NewForbidden(accounts, account.Name,
+				fmt.Errorf("the account workspace still contains %d accounts, they have to be deleted first", children))
+		}
+	}
+	return nil, nil
+}
+
+var _ webhook.CustomValidator = &AccountValidator{}
+
+// getAccountInfo returns the AccountInfo of the workspace in the context, nil if the workspace does not belong to an
+// account.
+func (v *AccountValidator) getAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	accountInfo := &AccountInfo{}
+	err := v.Client.Get(ctx, client.ObjectKey{Name: "account"}, accountInfo)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo, nil
+}
+
+// validateHierarchy checks the account type and the depth of the account against the AccountInfo of the workspace the
+// account is created in.
+func (v *AccountValidator) validateHierarchy(ctx context.Context, account *Account, parent *AccountInfo) (*field.Error, error) {
+	typePath := field.NewPath("spec", "type")
+	if err := v.AccountTypes.ValidatePlacement(account.Spec.Type, parent); err != nil {
+		return field.Forbidden(typePath, err.Error()), nil
+	}
+	if parent == nil {
+		return nil, nil
+	}
+
+	err := ValidateDepth(ctx, v.Client, v.DepthLimits, parent)
+	var depthErr *DepthExceededError
+	switch {
+	case errors.As(err, &depthErr):
+		return field.Forbidden(typePath, depthErr.Error()), nil
+	case err != nil:
+		return nil, err
+	}
+	return nil, nil
+}
+
+// validateQuota counts the accounts in the workspace the account is created in. Accounts that are being deleted do not
+// count against the quota.
+func (v *AccountValidator) validateQuota(ctx context.Context, account *Account, parent *AccountInfo) error {
+	if parent == nil {
+		return nil
+	}
+
+	limit, limited := v.ChildAccountLimits.For(parent)
+	if !limited {
+		return nil
+	}
+
+	accounts := &AccountList{}
+	if err := v.Client.List(ctx, accounts); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	used := 0
+	for _, existing := range accounts.Items {
+		if existing.DeletionTimestamp == nil && existing.Name != account.Name {
+			used++
+		}
+	}
+	if used >= limit {
+		quotaErr := &ChildAccountQuotaExceededError{Path: parent.Spec.Account.Path, Limit: limit, Used: used}
+		return apierrors.NewForbidden(GroupVersion.WithResource("accounts").GroupResource(), account.Name, quotaErr)
+	}
+	return nil
+}
+
+// countChildAccounts returns the number of accounts in the workspace of the account, 0 if the workspace does not exist
+func (v *AccountValidator) countChildAccounts(ctx context.Context, account *Account) (int, error) {
+	if cluster := account.GetAnnotations()[logicalcluster.AnnotationKey]; cluster != "" {
+		ctx = kontext.WithCluster(ctx, logicalcluster.Name(cluster))
+	}
+	workspace := &kcptenancyv1alpha.Workspace{}
+	err := v.Client.Get(ctx, client.ObjectKey{Name: account.Name}, workspace)
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if workspace.Spec.Cluster == "" {
+		return 0, nil
+	}
+
+	children := &AccountList{}
+	if err := v.Client.List(kontext.WithCluster(ctx, logicalcluster.Name(workspace.Spec.Cluster)), children); err != nil {
+		return 0, err
+	}
+	return len(children.Items), nil
+}
+
+// validateAccountSpec validates the spec of a new account, or on update the fields that differ from oldAccount. Stored
+// accounts that became invalid, like accounts created before the webhook, can still be updated as long as the invalid
+// fields are not touched.
+func (v *AccountValidator) validateAccountSpec(account, oldAccount *Account) field.ErrorList {
+	specPath := field.NewPath("spec")
+
+	var errs field.ErrorList
+	if oldAccount == nil || account.Spec.DisplayName != oldAccount.Spec.DisplayName {
+		if strings.TrimSpace(account.Spec.DisplayName) == "" {
+			errs = append(errs, field.Required(specPath.Child("displayName"), "must not be empty or whitespace only"))
+		}
+	}
+	// stored data that does not match a schema registered later is reported by the DataValid condition instead
+	if oldAccount == nil || !apiequality.Semantic.DeepEqual(account.Spec.Data, oldAccount.Spec.Data) {
+		errs = append(errs, v.DataSchemas.Validate(specPath.Child("data"), string(account.Spec.Type), account.Spec.Data)...)
+	}
+	if location := account.Spec.WorkspaceLocation; location != nil {
+		errs = append(errs, metav1validation.ValidateLabelSelector(location.Selector, metav1validation.LabelSelectorValidationOptions{}, specPath.Child("workspaceLocation", "selector"))...)
+	}
+
+	for i, extension := range account.Spec.Extensions {
+		if oldAccount != nil && slices.ContainsFunc(oldAccount.Spec.Extensions, func(old Extension) bool {
+			return apiequality.Semantic.DeepEqual(old, extension)
+		}) {
+			continue
+		}
+		path := specPath.Child("extensions").Index(i)
+		if extension.APIVersion == "" {
+			errs = append(errs, field.Required(path.Child("apiVersion"), ""))
+		}
+		if extension.Kind == "" {
+			errs = append(errs, field.Required(path.Child("kind"), ""))
+		}
+		if len(extension.MetadataGoTemplate.Raw) > 0 {
+			if err := templating.Validate(path.Child("metadataGoTemplate"), extension.MetadataGoTemplate.Raw); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := templating.Validate(path.Child("specGoTemplate"), extension.SpecGoTemplate.Raw); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func toInvalidError(account *Account, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Account").GroupKind(), account.Name, errs)
+}
@@ -0,0 +1,568 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/pkg/dataschema"
+)
+
+func newWebhookTestAccount() *v1alpha1.Account {
+	return &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-account"},
+		Spec: v1alpha1.AccountSpec{
+			Type:        v1alpha1.AccountTypeAccount,
+			DisplayName: "Test Account",
+			Creator:     ptr.To("creator"),
+			Extensions: []v1alpha1.Extension{{
+				TypeMeta:       metav1.TypeMeta{APIVersion: "example.openmfp.org/v1alpha1", Kind: "ExampleExtension"},
+				SpecGoTemplate: apiextensionsv1.JSON{Raw: []byte(`{"owner":"{{ .Account.metadata.name }}"}`)},
+			}},
+		},
+	}
+}
+
+func requestContext(operation admissionv1.Operation, groups ...string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			UserInfo:  authenticationv1.UserInfo{Username: "user", Groups: groups},
+		},
+	})
+}
+
+func TestAccountDefaulter(t *testing.T) {
+	defaulter := &v1alpha1.AccountDefaulter{}
+
+	t.Run("sets the creator on create", func(t *testing.T) {
+		account := newWebhookTestAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create), account))
+		assert.Equal(t, "user", *account.Spec.Creator)
+	})
+
+	t.Run("keeps the creator on update", func(t *testing.T) {
+		account := newWebhookTestAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Update), account))
+		assert.Equal(t, "creator", *account.Spec.Creator)
+	})
+
+	t.Run("restores a missing creator on update", func(t *testing.T) {
+		oldRaw, err := json.Marshal(newWebhookTestAccount())
+		require.NoError(t, err)
+		ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "user"},
+				OldObject: runtime.RawExtension{Raw: oldRaw},
+			},
+		})
+
+		account := newWebhookTestAccount()
+		account.Spec.Creator = nil
+		require.NoError(t, defaulter.Default(ctx, account))
+		assert.Equal(t, "creator", *account.Spec.Creator)
+	})
+
+	t.Run("adds the extensions of the account type on create", func(t *testing.T) {
+		accountTypes, err := v1alpha1.NewAccountTypeRegistry([]v1alpha1.AccountTypeDefinition{
+			{Name: v1alpha1.AccountTypeOrg, OwnsStore: true},
+			{Name: v1alpha1.AccountTypeAccount, AllowedParents: []v1alpha1.AccountType{v1alpha1.AccountTypeOrg}, Extensions: []v1alpha1.Extension{
+				{TypeMeta: metav1.TypeMeta{APIVersion: "example.openmfp.org/v1alpha1", Kind: "ExampleExtension"}},
+				{TypeMeta: metav1.TypeMeta{APIVersion: "example.openmfp.org/v1alpha1", Kind: "Budget"}},
+			}},
+		})
+		require.NoError(t, err)
+		defaulter := &v1alpha1.AccountDefaulter{AccountTypes: accountTypes}
+
+		account := newWebhookTestAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create), account))
+		require.Len(t, account.Spec.Extensions, 2)
+		// the extension of the account is kept
+		assert.Equal(t, newWebhookTestAccount().Spec.Extensions[0], account.Spec.Extensions[0])
+		assert.Equal(t, "Budget", account.Spec.Extensions[1].Kind)
+
+		account = newWebhookTestAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Update), account))
+		assert.Len(t, account.Spec.Extensions, 1)
+	})
+}
+
+// newCreatorAuthorizer allows callers of the automation group to set the creator and records the reviews
+func newCreatorAuthorizer(t *testing.T, reviews *[]*authorizationv1.SubjectAccessReview) *v1alpha1.CreatorAuthorizer {
+	scheme := runtime.NewScheme()
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+
+	authorizer := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			cluster, _ := kontext.ClusterFrom(ctx)
+			assert.Equal(t, "some-cluster", cluster.String())
+			review.Status.Allowed = slices.Contains(review.Spec.Groups, "automation")
+			*reviews = append(*reviews, review)
+			return nil
+		},
+	}).Build()
+	return &v1alpha1.CreatorAuthorizer{
+		Client:     authorizer,
+		Permission: authorizationv1.ResourceAttributes{Verb: "create", Group: "core.openmfp.org", Resource: "accounts", Subresource: "creator"},
+	}
+}
+
+func TestAccountDefaulter_CreatorOverride(t *testing.T) {
+	var reviews []*authorizationv1.SubjectAccessReview
+	defaulter := &v1alpha1.AccountDefaulter{CreatorAuthorizer: newCreatorAuthorizer(t, &reviews)}
+
+	newAccount := func() *v1alpha1.Account {
+		account := newWebhookTestAccount()
+		account.Annotations = map[string]string{"kcp.io/cluster": "some-cluster"}
+		return account
+	}
+
+	t.Run("keeps the creator set by an authorized caller", func(t *testing.T) {
+		reviews = nil
+		account := newAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create, "automation"), account))
+		assert.Equal(t, "creator", *account.Spec.Creator)
+
+		require.Len(t, reviews, 1)
+		assert.Equal(t, "user", reviews[0].Spec.User)
+		assert.Equal(t, &authorizationv1.ResourceAttributes{
+			Verb: "create", Group: "core.openmfp.org", Resource: "accounts", Subresource: "creator", Name: "test-account",
+		}, reviews[0].Spec.ResourceAttributes)
+	})
+
+	t.Run("forces the caller as creator for unauthorized callers", func(t *testing.T) {
+		reviews = nil
+		account := newAccount()
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create, "developers"), account))
+		assert.Equal(t, "user", *account.Spec.Creator)
+		assert.Len(t, reviews, 1)
+	})
+
+	t.Run("keeps the creator of accounts restored by the operator", func(t *testing.T) {
+		reviews = nil
+		// the creator override is disabled, only the operator may set the creator
+		operatorDefaulter := &v1alpha1.AccountDefaulter{CreatorAuthorizer: &v1alpha1.CreatorAuthorizer{Operator: "user"}}
+		account := newAccount()
+		require.NoError(t, operatorDefaulter.Default(requestContext(admissionv1.Create), account))
+		assert.Equal(t, "creator", *account.Spec.Creator)
+		assert.Empty(t, reviews)
+	})
+
+	t.Run("does not review callers that are the creator", func(t *testing.T) {
+		reviews = nil
+		account := newAccount()
+		account.Spec.Creator = nil
+		require.NoError(t, defaulter.Default(requestContext(admissionv1.Create), account))
+		assert.Equal(t, "user", *account.Spec.Creator)
+		assert.Empty(t, reviews)
+	})
+}
+
+func TestAccountValidator_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(account *v1alpha1.Account)
+		fields []string
+	}{
+		{
+			name:   "valid account",
+			modify: func(account *v1alpha1.Account) {},
+		},
+		{
+			name:   "whitespace display name",
+			modify: func(account *v1alpha1.Account) { account.Spec.DisplayName = "  " },
+			fields: []string{"spec.displayName"},
+		},
+		{
+			name: "missing apiVersion and kind",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Extensions[0].TypeMeta = metav1.TypeMeta{}
+			},
+			fields: []string{"spec.extensions[0].apiVersion", "spec.extensions[0].kind"},
+		},
+		{
+			name: "unparseable spec template",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Extensions[0].SpecGoTemplate.Raw = []byte(`{"owner":{"name":"{{ .Account.metadata.name "}}`)
+			},
+			fields: []string{"spec.extensions[0].specGoTemplate.owner.name"},
+		},
+		{
+			name: "unparseable metadata template",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Extensions[0].MetadataGoTemplate.Raw = []byte(`{"name":"{{ unknown }}"}`)
+			},
+			fields: []string{"spec.extensions[0].metadataGoTemplate.name"},
+		},
+		{
+			name: "valid data",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)}
+			},
+		},
+		{
+			name: "data not matching the schema",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":1}`)}
+			},
+			fields: []string{"spec.data.costCenter"},
+		},
+	}
+
+	schemas, err := dataschema.New(map[string]apiextensionsv1.JSONSchemaProps{
+		string(v1alpha1.AccountTypeAccount): {
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"costCenter": {Type: "string"}},
+		},
+	})
+	require.NoError(t, err)
+
+	validator := &v1alpha1.AccountValidator{DataSchemas: schemas}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account := newWebhookTestAccount()
+			test.modify(account)
+
+			_, err := validator.ValidateCreate(requestContext(admissionv1.Create), account)
+			assertInvalidFields(t, err, test.fields)
+		})
+	}
+}
+
+func TestAccountValidator_ValidateUpdate(t *testing.T) {
+	invalid := func(account *v1alpha1.Account) {
+		account.Spec.DisplayName = ""
+		account.Spec.Extensions[0].SpecGoTemplate.Raw = []byte(`{"owner":"{{ unknown }}"}`)
+		account.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":1}`)}
+	}
+
+	tests := []struct {
+		name   string
+		groups []string
+		// stored is applied to the old and the new account
+		stored func(account *v1alpha1.Account)
+		modify func(account *v1alpha1.Account)
+		fields []string
+	}{
+		{
+			name:   "display name change",
+			modify: func(account *v1alpha1.Account) { account.Spec.DisplayName = "Other" },
+		},
+		{
+			name:   "type change",
+			modify: func(account *v1alpha1.Account) { account.Spec.Type = v1alpha1.AccountTypeOrg },
+			fields: []string{"spec.type"},
+		},
+		{
+			name:   "creator change",
+			modify: func(account *v1alpha1.Account) { account.Spec.Creator = ptr.To("someone-else") },
+			fields: []string{"spec.creator"},
+		},
+		{
+			name:   "creator removal",
+			modify: func(account *v1alpha1.Account) { account.Spec.Creator = nil },
+			fields: []string{"spec.creator"},
+		},
+		{
+			name:   "creator change by a caller allowed to set the creator of new accounts",
+			groups: []string{"system:authenticated", "automation"},
+			modify: func(account *v1alpha1.Account) { account.Spec.Creator = ptr.To("someone-else") },
+			fields: []string{"spec.creator"},
+		},
+		{
+			name: "workspace location change",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.WorkspaceLocation = &v1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "a"}}}
+			},
+			fields: []string{"spec.workspaceLocation"},
+		},
+		{
+			name: "invalid workspace location",
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.WorkspaceLocation = &v1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "not valid"}}}
+			},
+			fields: []string{"spec.workspaceLocation.selector.matchLabels", "spec.workspaceLocation"},
+		},
+		{
+			name:   "finalizer change of an invalid account",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) { account.Finalizers = []string{"account.core.openmfp.org/finalizer"} },
+		},
+		{
+			name:   "finalizer removal of a deleted invalid account",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) {
+				account.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				account.Finalizers = nil
+			},
+		},
+		{
+			name:   "display name change of an invalid account",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) { account.Spec.DisplayName = " " },
+			fields: []string{"spec.displayName"},
+		},
+		{
+			name:   "data change of an invalid account",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":2}`)}
+			},
+			fields: []string{"spec.data.costCenter"},
+		},
+		{
+			name:   "data fixed",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)}
+			},
+		},
+		{
+			name:   "new invalid extension",
+			stored: invalid,
+			modify: func(account *v1alpha1.Account) {
+				account.Spec.Extensions = append(account.Spec.Extensions, v1alpha1.Extension{
+					TypeMeta:       metav1.TypeMeta{APIVersion: "example.openmfp.org/v1alpha1", Kind: "OtherExtension"},
+					SpecGoTemplate: apiextensionsv1.JSON{Raw: []byte(`{"name":"{{ unknown }}"}`)},
+				})
+			},
+			fields: []string{"spec.extensions[1].specGoTemplate.name"},
+		},
+	}
+
+	schemas, err := dataschema.New(map[string]apiextensionsv1.JSONSchemaProps{
+		string(v1alpha1.AccountTypeAccount): {
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"costCenter": {Type: "string"}},
+		},
+	})
+	require.NoError(t, err)
+
+	validator := &v1alpha1.AccountValidator{DataSchemas: schemas}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldAccount := newWebhookTestAccount()
+			oldAccount.Annotations = map[string]string{"kcp.io/cluster": "some-cluster"}
+			account := newWebhookTestAccount()
+			account.Annotations = map[string]string{"kcp.io/cluster": "some-cluster"}
+			if test.stored != nil {
+				test.stored(oldAccount)
+				test.stored(account)
+			}
+			test.modify(account)
+
+			_, err := validator.ValidateUpdate(requestContext(admissionv1.Update, test.groups...), oldAccount, account)
+			assertInvalidFields(t, err, test.fields)
+		})
+	}
+}
+
+func assertInvalidFields(t *testing.T, err error, fields []string) {
+	t.Helper()
+	if len(fields) == 0 {
+		assert.NoError(t, err)
+		return
+	}
+
+	require.Error(t, err)
+	require.True(t, apierrors.IsInvalid(err))
+	var actual []string
+	for _, cause := range err.(*apierrors.StatusError).ErrStatus.Details.Causes {
+		actual = append(actual, cause.Field)
+	}
+	assert.Equal(t, fields, actual)
+}
+
+func TestAccountValidator_Placement(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	orgInfo := &v1alpha1.AccountInfo{
+		ObjectMeta: metav1.ObjectMeta{Name: "account"},
+		Spec: v1alpha1.AccountInfoSpec{
+			Account: v1alpha1.AccountLocation{Name: "org", Type: v1alpha1.AccountTypeOrg, Path: "root:orgs:org"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		accountType v1alpha1.AccountType
+		parent      *v1alpha1.AccountInfo
+		fields      []string
+	}{
+		{name: "org in provider workspace", accountType: v1alpha1.AccountTypeOrg},
+		{name: "org in org workspace", accountType: v1alpha1.AccountTypeOrg, parent: orgInfo, fields: []string{"spec.type"}},
+		{name: "account in org workspace", accountType: v1alpha1.AccountTypeAccount, parent: orgInfo},
+		{name: "account in provider workspace", accountType: v1alpha1.AccountTypeAccount, fields: []string{"spec.type"}},
+		{name: "unknown type", accountType: "project", parent: orgInfo, fields: []string{"spec.type"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if test.parent != nil {
+				builder = builder.WithObjects(test.parent.DeepCopy())
+			}
+			validator := &v1alpha1.AccountValidator{Client: builder.Build()}
+
+			account := newWebhookTestAccount()
+			account.Spec.Type = test.accountType
+			account.Annotations = map[string]string{"kcp.io/cluster": "some-cluster"}
+
+			_, err := validator.ValidateCreate(requestContext(admissionv1.Create), account)
+			assertInvalidFields(t, err, test.fields)
+		})
+	}
+}
+
+func TestAccountValidator_Depth(t *testing.T) {
+	infos, cluster := newAccountInfoChain(2)
+
+	tests := []struct {
+		name   string
+		limits v1alpha1.DepthLimits
+		fields []string
+	}{
+		{name: "no limit"},
+		{name: "within the limit", limits: v1alpha1.DepthLimits{Default: 3}},
+		{name: "exceeds the limit", limits: v1alpha1.DepthLimits{Default: 2}, fields: []string{"spec.type"}},
+		{name: "organization override", limits: v1alpha1.DepthLimits{Default: 2, Organizations: map[string]int{"org": 5}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := &v1alpha1.AccountValidator{Client: newClusterClient(infos), DepthLimits: test.limits}
+
+			account := newWebhookTestAccount()
+			account.Annotations = map[string]string{"kcp.io/cluster": cluster}
+
+			_, err := validator.ValidateCreate(requestContext(admissionv1.Create), account)
+			assertInvalidFields(t, err, test.fields)
+		})
+	}
+}
+
+func TestAccountValidator_Quota(t *testing.T) {
+	infos, _ := newAccountInfoChain(1)
+
+	existing := func(name string, deleting bool) client.Object {
+		account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if deleting {
+			account.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			account.Finalizers = []string{"account.core.openmfp.org/finalizer"}
+		}
+		return account
+	}
+
+	tests := []struct {
+		name     string
+		cluster  string
+		limits   v1alpha1.ChildAccountLimits
+		existing []client.Object
+		err      string
+	}{
+		{name: "no limit", cluster: "cluster-0", existing: []client.Object{existing("a", false)}},
+		{name: "within the limit", cluster: "cluster-0", limits: v1alpha1.ChildAccountLimits{Org: 2}, existing: []client.Object{existing("a", false)}},
+		{
+			name: "exceeds the limit", cluster: "cluster-0", limits: v1alpha1.ChildAccountLimits{Org: 2},
+			existing: []client.Object{existing("a", false), existing("b", false)},
+			err:      `accounts.core.openmfp.org "test-account" is forbidden: exceeded quota of the workspace "root:orgs:org": used 2 of 2 child accounts`,
+		},
+		{
+			name: "deleted accounts do not count", cluster: "cluster-0", limits: v1alpha1.ChildAccountLimits{Org: 2},
+			existing: []client.Object{existing("a", false), existing("b", true)},
+		},
+		{
+			name: "organization overrides the limit", cluster: "cluster-1",
+			limits:   v1alpha1.ChildAccountLimits{Account: 1, AccountOverrides: map[string]int{"org": 3}},
+			existing: []client.Object{existing("a", false), existing("b", false)},
+		},
+		{
+			name: "override of 0 allows no accounts", cluster: "cluster-0", limits: v1alpha1.ChildAccountLimits{OrgOverrides: map[string]int{"org": 0}},
+			err: `accounts.core.openmfp.org "test-account" is forbidden: exceeded quota of the workspace "root:orgs:org": used 0 of 0 child accounts`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := &v1alpha1.AccountValidator{Client: newClusterClient(infos, test.existing...), ChildAccountLimits: test.limits}
+
+			account := newWebhookTestAccount()
+			account.Annotations = map[string]string{"kcp.io/cluster": test.cluster}
+
+			_, err := validator.ValidateCreate(requestContext(admissionv1.Create), account)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, apierrors.IsForbidden(err))
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestAccountValidator_ValidateDelete(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, kcptenancyv1alpha.AddToScheme(scheme))
+	workspace := &kcptenancyv1alpha.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-account"},
+		Spec:       kcptenancyv1alpha.WorkspaceSpec{Cluster: "child-cluster"},
+	}
+	child := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "child"}}
+
+	tests := []struct {
+		name                string
+		protected           bool
+		protectWithChildren bool
+		objects             []client.Object
+		forbidden           bool
+	}{
+		{name: "unprotected account", objects: []client.Object{workspace, child}},
+		{name: "protected account", protected: true, forbidden: true},
+		{name: "account with children", protectWithChildren: true, objects: []client.Object{workspace, child}, forbidden: true},
+		{name: "account without children", protectWithChildren: true, objects: []client.Object{workspace}},
+		{name: "account without workspace", protectWithChildren: true, objects: []client.Object{child}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := &v1alpha1.AccountValidator{
+				Client:                      fake.NewClientBuilder().WithScheme(scheme).WithObjects(test.objects...).Build(),
+				ProtectAccountsWithChildren: test.protectWithChildren,
+			}
+
+			account := newWebhookTestAccount()
+			account.Spec.DeletionProtection = test.protected
+
+			_, err := validator.ValidateDelete(requestContext(admissionv1.Delete), account)
+			if !test.forbidden {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, apierrors.IsForbidden(err))
+		})
+	}
+}
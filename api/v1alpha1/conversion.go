@@ -0,0 +1,9 @@
+package v1alpha1
+
+// v1alpha1 is the storage version and the hub all other versions convert from and to.
+
+// Hub marks Account as the conversion hub.
+func (*Account) Hub() {}
+
+// Hub marks AccountInfo as the conversion hub.
+func (*AccountInfo) Hub() {}
@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+)
+
+// DepthLimits is the maximum nesting depth of accounts below their organization. Accounts created directly in an
+// organization have a depth of 1. A limit of 0 disables the check.
+//
+// +kubebuilder:object:generate=false
+type DepthLimits struct {
+	// Default applies to all organizations without an override
+	Default int
+	// Organizations overrides the limit by organization name
+	Organizations map[string]int
+}
+
+// ParseDepthLimits parses the overrides, a comma separated list of organization=depth pairs.
+func ParseDepthLimits(defaultDepth int, overrides string) (DepthLimits, error) {
+	limits := DepthLimits{Default: defaultDepth, Organizations: map[string]int{}}
+	if defaultDepth < 0 {
+		return limits, fmt.Errorf("the maximum depth %d must not be negative", defaultDepth)
+	}
+
+	organizations, err := parseOrganizationOverrides(overrides, "maximum depth", "depth")
+	if err != nil {
+		return limits, err
+	}
+	limits.Organizations = organizations
+	return limits, nil
+}
+
+// parseOrganizationOverrides parses a comma separated list of organization=value pairs of the named setting.
+func parseOrganizationOverrides(overrides, setting, unit string) (map[string]int, error) {
+	organizations := map[string]int{}
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		organization, value, found := strings.Cut(override, "=")
+		if !found {
+			return nil, fmt.Errorf("the %s override %q is not in the format organization=%s", setting, override, unit)
+		}
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("the %s of organization %q must be a number of at least 0", setting, organization)
+		}
+		organizations[strings.TrimSpace(organization)] = parsed
+	}
+	return organizations, nil
+}
+
+// Enabled reports whether a limit applies to any organization.
+func (l DepthLimits) Enabled() bool {
+	for _, depth := range l.Organizations {
+		if depth > 0 {
+			return true
+		}
+	}
+	return l.Default > 0
+}
+
+// For returns the limit of the given organization.
+func (l DepthLimits) For(organization string) int {
+	if depth, ok := l.Organizations[organization]; ok {
+		return depth
+	}
+	return l.Default
+}
+
+// AccountDepth returns the depth of the account the AccountInfo belongs to, 0 for organizations. It follows the parent
+// chain of the AccountInfos and stops as soon as the depth exceeds limit, so the result is only exact up to limit+1.
+func AccountDepth(ctx context.Context, c client.Reader, info *AccountInfo, limit int) (int, error) {
+	depth := 0
+	for info.Spec.ParentAccount != nil {
+		depth++
+		if depth > limit {
+			return depth, nil
+		}
+
+		parent := &AccountInfo{}
+		parentCtx := kontext.WithCluster(ctx, logicalcluster.Name(info.Spec.ParentAccount.GeneratedClusterId))
+		if err := c.Get(parentCtx, client.ObjectKey{Name: "account"}, parent); err != nil {
+			return 0, err
+		}
+		info = parent
+	}
+	return depth, nil
+}
+
+// ValidateDepth checks whether a new account may be created below the account of the parent AccountInfo.
+func ValidateDepth(ctx context.Context, c client.Reader, limits DepthLimits, parent *AccountInfo) error {
+	return ValidateSubtreeDepth(ctx, c, limits, parent, 0)
+}
+
+// ValidateSubtreeDepth checks whether an account with height levels of accounts below it may be attached below the
+// account of the parent AccountInfo.
+func ValidateSubtreeDepth(ctx context.Context, c client.Reader, limits DepthLimits, parent *AccountInfo, height int) error {
+	limit := limits.For(parent.Spec.Organization.Name)
+	if limit == 0 {
+		return nil
+	}
+
+	parentDepth, err := AccountDepth(ctx, c, parent, limit)
+	if err != nil {
+		return err
+	}
+	if parentDepth+1+height > limit {
+		return &DepthExceededError{Organization: parent.Spec.Organization.Name, Limit: limit}
+	}
+	return nil
+}
+
+// DepthExceededError reports an account that is nested deeper than allowed
+//
+// +kubebuilder:object:generate=false
+type DepthExceededError struct {
+	Organization string
+	Limit        int
+}
+
+func (e *DepthExceededError) Error() string {
+	return fmt.Sprintf("accounts in the organization %q can be nested at most %d levels deep", e.Organization, e.Limit)
+}
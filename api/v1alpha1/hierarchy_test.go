@@ -0,0 +1,139 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+// newAccountInfoChain returns the AccountInfos of an organization and depth accounts nested below it, keyed by the
+// cluster of their workspace, and the cluster of the deepest account.
+func newAccountInfoChain(depth int) (map[string]*v1alpha1.AccountInfo, string) {
+	org := v1alpha1.AccountLocation{Name: "org", Type: v1alpha1.AccountTypeOrg, GeneratedClusterId: "cluster-0", Path: "root:orgs:org"}
+	infos := map[string]*v1alpha1.AccountInfo{
+		"cluster-0": {
+			ObjectMeta: metav1.ObjectMeta{Name: "account"},
+			Spec:       v1alpha1.AccountInfoSpec{Account: org, Organization: org},
+		},
+	}
+
+	parent := org
+	for i := 1; i <= depth; i++ {
+		location := v1alpha1.AccountLocation{
+			Name: fmt.Sprintf("account-%d", i), Type: v1alpha1.AccountTypeAccount, GeneratedClusterId: fmt.Sprintf("cluster-%d", i),
+		}
+		location.Path = fmt.Sprintf("%s:%s", parent.Path, location.Name)
+		infos[location.GeneratedClusterId] = &v1alpha1.AccountInfo{
+			ObjectMeta: metav1.ObjectMeta{Name: "account"},
+			Spec:       v1alpha1.AccountInfoSpec{Account: location, Organization: org, ParentAccount: parent.DeepCopy()},
+		}
+		parent = location
+	}
+	return infos, parent.GeneratedClusterId
+}
+
+// newClusterClient returns a client that serves the AccountInfo of the cluster in the context and the given objects
+func newClusterClient(infos map[string]*v1alpha1.AccountInfo, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, _ client.WithWatch, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			info, ok := infos[cluster.String()]
+			if !ok {
+				return apierrors.NewNotFound(schema.GroupResource{Group: "core.openmfp.org", Resource: "accountinfos"}, key.Name)
+			}
+			info.DeepCopyInto(obj.(*v1alpha1.AccountInfo))
+			return nil
+		},
+	}).Build()
+}
+
+func TestParseDepthLimits(t *testing.T) {
+	limits, err := v1alpha1.ParseDepthLimits(3, " org-a=5, org-b = 0 ,")
+	require.NoError(t, err)
+	assert.Equal(t, 3, limits.For("org"))
+	assert.Equal(t, 5, limits.For("org-a"))
+	assert.Equal(t, 0, limits.For("org-b"))
+	assert.True(t, limits.Enabled())
+
+	limits, err = v1alpha1.ParseDepthLimits(0, "")
+	require.NoError(t, err)
+	assert.False(t, limits.Enabled())
+
+	limits, err = v1alpha1.ParseDepthLimits(0, "org=2")
+	require.NoError(t, err)
+	assert.True(t, limits.Enabled())
+
+	_, err = v1alpha1.ParseDepthLimits(-1, "")
+	assert.Error(t, err)
+	_, err = v1alpha1.ParseDepthLimits(3, "org")
+	assert.EqualError(t, err, `the maximum depth override "org" is not in the format organization=depth`)
+	_, err = v1alpha1.ParseDepthLimits(3, "org=deep")
+	assert.EqualError(t, err, `the maximum depth of organization "org" must be a number of at least 0`)
+}
+
+func TestAccountDepth(t *testing.T) {
+	infos, cluster := newAccountInfoChain(3)
+	c := newClusterClient(infos)
+
+	depth, err := v1alpha1.AccountDepth(context.Background(), c, infos["cluster-0"], 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, depth)
+
+	depth, err = v1alpha1.AccountDepth(context.Background(), c, infos[cluster], 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, depth)
+
+	// the walk stops once the limit is exceeded
+	depth, err = v1alpha1.AccountDepth(context.Background(), c, infos[cluster], 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, depth)
+
+	delete(infos, "cluster-1")
+	_, err = v1alpha1.AccountDepth(context.Background(), c, infos[cluster], 10)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestValidateDepth(t *testing.T) {
+	infos, cluster := newAccountInfoChain(2)
+	c := newClusterClient(infos)
+	parent := infos[cluster]
+
+	assert.NoError(t, v1alpha1.ValidateDepth(context.Background(), c, v1alpha1.DepthLimits{}, parent))
+	assert.NoError(t, v1alpha1.ValidateDepth(context.Background(), c, v1alpha1.DepthLimits{Default: 3}, parent))
+
+	err := v1alpha1.ValidateDepth(context.Background(), c, v1alpha1.DepthLimits{Default: 2}, parent)
+	var depthErr *v1alpha1.DepthExceededError
+	require.ErrorAs(t, err, &depthErr)
+	assert.EqualError(t, err, `accounts in the organization "org" can be nested at most 2 levels deep`)
+
+	limits := v1alpha1.DepthLimits{Default: 2, Organizations: map[string]int{"org": 0}}
+	assert.NoError(t, v1alpha1.ValidateDepth(context.Background(), c, limits, parent))
+}
+
+func TestValidateSubtreeDepth(t *testing.T) {
+	infos, cluster := newAccountInfoChain(2)
+	c := newClusterClient(infos)
+	parent := infos[cluster]
+
+	assert.NoError(t, v1alpha1.ValidateSubtreeDepth(context.Background(), c, v1alpha1.DepthLimits{Default: 5}, parent, 2))
+
+	err := v1alpha1.ValidateSubtreeDepth(context.Background(), c, v1alpha1.DepthLimits{Default: 4}, parent, 2)
+	var depthErr *v1alpha1.DepthExceededError
+	assert.ErrorAs(t, err, &depthErr)
+}
@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+// ChildAccountLimits is the number of accounts that may be created in an account workspace. The limits are part of the
+// operator configuration, tenants can not change them. A default of 0 disables the check, an override of 0 allows no
+// accounts at all.
+//
+// +kubebuilder:object:generate=false
+type ChildAccountLimits struct {
+	// Org applies to the workspaces of organizations
+	Org int
+	// Account applies to the workspaces of accounts
+	Account int
+	// OrgOverrides overrides Org by organization name
+	OrgOverrides map[string]int
+	// AccountOverrides overrides Account for the accounts of an organization, by organization name
+	AccountOverrides map[string]int
+}
+
+// ParseChildAccountLimits parses the overrides, comma separated lists of organization=count pairs.
+func ParseChildAccountLimits(org, account int, orgOverrides, accountOverrides string) (ChildAccountLimits, error) {
+	limits := ChildAccountLimits{Org: org, Account: account}
+	if org < 0 || account < 0 {
+		return limits, fmt.Errorf("the maximum number of child accounts must not be negative")
+	}
+
+	var err error
+	if limits.OrgOverrides, err = parseOrganizationOverrides(orgOverrides, "maximum number of child accounts", "count"); err != nil {
+		return limits, err
+	}
+	if limits.AccountOverrides, err = parseOrganizationOverrides(accountOverrides, "maximum number of child accounts per account", "count"); err != nil {
+		return limits, err
+	}
+	return limits, nil
+}
+
+// For returns the limit of the workspace of the AccountInfo parent and whether the workspace is limited at all. The
+// workspace of an AccountInfo without parent is an organization workspace.
+func (l ChildAccountLimits) For(parent *AccountInfo) (int, bool) {
+	defaultLimit, overrides := l.Account, l.AccountOverrides
+	if parent.Spec.ParentAccount == nil {
+		defaultLimit, overrides = l.Org, l.OrgOverrides
+	}
+	if limit, ok := overrides[parent.Spec.Organization.Name]; ok {
+		return limit, true
+	}
+	return defaultLimit, defaultLimit > 0
+}
+
+// ChildAccountQuotaExceededError reports a workspace that already contains as many accounts as allowed
+//
+// +kubebuilder:object:generate=false
+type ChildAccountQuotaExceededError struct {
+	Path  string
+	Limit int
+	Used  int
+}
+
+func (e *ChildAccountQuotaExceededError) Error() string {
+	return fmt.Sprintf("exceeded quota of the workspace %q: used %d of %d child accounts", e.Path, e.Used, e.Limit)
+}
@@ -0,0 +1,58 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+func TestParseChildAccountLimits(t *testing.T) {
+	limits, err := v1alpha1.ParseChildAccountLimits(10, 5, "org-a=20, org-b = 0 ,", "org-a=3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"org-a": 20, "org-b": 0}, limits.OrgOverrides)
+	assert.Equal(t, map[string]int{"org-a": 3}, limits.AccountOverrides)
+
+	_, err = v1alpha1.ParseChildAccountLimits(-1, 0, "", "")
+	assert.Error(t, err)
+	_, err = v1alpha1.ParseChildAccountLimits(0, 0, "org", "")
+	assert.EqualError(t, err, `the maximum number of child accounts override "org" is not in the format organization=count`)
+	_, err = v1alpha1.ParseChildAccountLimits(0, 0, "", "org=many")
+	assert.EqualError(t, err, `the maximum number of child accounts per account of organization "org" must be a number of at least 0`)
+}
+
+func TestChildAccountLimits_For(t *testing.T) {
+	limits, err := v1alpha1.ParseChildAccountLimits(10, 0, "org-b=0", "org-a=3")
+	require.NoError(t, err)
+	workspace := func(organization string, account bool) *v1alpha1.AccountInfo {
+		org := v1alpha1.AccountLocation{Name: organization, Type: v1alpha1.AccountTypeOrg}
+		info := &v1alpha1.AccountInfo{Spec: v1alpha1.AccountInfoSpec{Account: org, Organization: org}}
+		if account {
+			info.Spec.Account = v1alpha1.AccountLocation{Name: "account", Type: v1alpha1.AccountTypeAccount}
+			info.Spec.ParentAccount = &org
+		}
+		return info
+	}
+
+	tests := []struct {
+		name    string
+		info    *v1alpha1.AccountInfo
+		limit   int
+		limited bool
+	}{
+		{name: "organization default", info: workspace("org-a", false), limit: 10, limited: true},
+		{name: "organization override of 0 allows no accounts", info: workspace("org-b", false), limit: 0, limited: true},
+		{name: "account default of 0 disables the limit", info: workspace("org-b", true), limit: 0, limited: false},
+		{name: "account override", info: workspace("org-a", true), limit: 3, limited: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limit, limited := limits.For(test.info)
+			assert.Equal(t, test.limit, limit)
+			assert.Equal(t, test.limited, limited)
+		})
+	}
+}
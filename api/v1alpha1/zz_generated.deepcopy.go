@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -53,28 +54,13 @@ func (in *Account) DeepCopyObject() runtime.Object {
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AccountDefaulter) DeepCopyInto(out *AccountDefaulter) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountDefaulter.
-func (in *AccountDefaulter) DeepCopy() *AccountDefaulter {
-	if in == nil {
-		return nil
-	}
-	out := new(AccountDefaulter)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountInfo) DeepCopyInto(out *AccountInfo) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountInfo.
@@ -131,13 +117,13 @@ func (in *AccountInfoList) DeepCopyObject() runtime.Object {
 func (in *AccountInfoSpec) DeepCopyInto(out *AccountInfoSpec) {
 	*out = *in
 	out.FGA = in.FGA
-	out.Account = in.Account
+	in.Account.DeepCopyInto(&out.Account)
 	if in.ParentAccount != nil {
 		in, out := &in.ParentAccount, &out.ParentAccount
 		*out = new(AccountLocation)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
-	out.Organization = in.Organization
+	in.Organization.DeepCopyInto(&out.Organization)
 	out.ClusterInfo = in.ClusterInfo
 }
 
@@ -154,6 +140,17 @@ func (in *AccountInfoSpec) DeepCopy() *AccountInfoSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountInfoStatus) DeepCopyInto(out *AccountInfoStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountInfoStatus.
@@ -201,6 +198,21 @@ func (in *AccountList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountLocation) DeepCopyInto(out *AccountLocation) {
 	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Creator != nil {
+		in, out := &in.Creator, &out.Creator
+		*out = new(string)
+		**out = **in
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountLocation.
@@ -213,6 +225,116 @@ func (in *AccountLocation) DeepCopy() *AccountLocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMove) DeepCopyInto(out *AccountMove) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMove.
+func (in *AccountMove) DeepCopy() *AccountMove {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMove)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountMove) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMoveList) DeepCopyInto(out *AccountMoveList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccountMove, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMoveList.
+func (in *AccountMoveList) DeepCopy() *AccountMoveList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMoveList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountMoveList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMoveSpec) DeepCopyInto(out *AccountMoveSpec) {
+	*out = *in
+	if in.Creator != nil {
+		in, out := &in.Creator, &out.Creator
+		*out = new(authenticationv1.UserInfo)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMoveSpec.
+func (in *AccountMoveSpec) DeepCopy() *AccountMoveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMoveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMoveStatus) DeepCopyInto(out *AccountMoveStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreviousParent != nil {
+		in, out := &in.PreviousParent, &out.PreviousParent
+		*out = new(AccountLocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMoveStatus.
+func (in *AccountMoveStatus) DeepCopy() *AccountMoveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMoveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 	*out = *in
@@ -238,6 +360,11 @@ func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 		*out = new(v1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WorkspaceLocation != nil {
+		in, out := &in.WorkspaceLocation, &out.WorkspaceLocation
+		*out = new(WorkspaceLocation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountSpec.
@@ -261,6 +388,22 @@ func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
 		}
 	}
 	in.NextReconcileTime.DeepCopyInto(&out.NextReconcileTime)
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(AccountWorkspaceStatus)
+		**out = **in
+	}
+	if in.RestorableUntil != nil {
+		in, out := &in.RestorableUntil, &out.RestorableUntil
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
@@ -273,6 +416,21 @@ func (in *AccountStatus) DeepCopy() *AccountStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountWorkspaceStatus) DeepCopyInto(out *AccountWorkspaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountWorkspaceStatus.
+func (in *AccountWorkspaceStatus) DeepCopy() *AccountWorkspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountWorkspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterInfo) DeepCopyInto(out *ClusterInfo) {
 	*out = *in
@@ -311,6 +469,31 @@ func (in *Extension) DeepCopy() *Extension {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionStatus) DeepCopyInto(out *ExtensionStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.ReadyCondition != nil {
+		in, out := &in.ReadyCondition, &out.ReadyCondition
+		*out = new(ObservedCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastDriftCorrectionTime != nil {
+		in, out := &in.LastDriftCorrectionTime, &out.LastDriftCorrectionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionStatus.
+func (in *ExtensionStatus) DeepCopy() *ExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FGAInfo) DeepCopyInto(out *FGAInfo) {
 	*out = *in
@@ -327,6 +510,25 @@ func (in *FGAInfo) DeepCopy() *FGAInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedCondition) DeepCopyInto(out *ObservedCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedCondition.
+func (in *ObservedCondition) DeepCopy() *ObservedCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreInfo) DeepCopyInto(out *StoreInfo) {
 	*out = *in
@@ -341,3 +543,23 @@ func (in *StoreInfo) DeepCopy() *StoreInfo {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceLocation) DeepCopyInto(out *WorkspaceLocation) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceLocation.
+func (in *WorkspaceLocation) DeepCopy() *WorkspaceLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceLocation)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,125 @@
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &Account{}
+
+// ConvertTo converts this Account to the hub version.
+func (src *Account) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Account)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = v1alpha1.AccountSpec{
+		Type:                    v1alpha1.AccountType(src.Spec.Type),
+		DisplayName:             src.Spec.DisplayName,
+		Description:             src.Spec.Description,
+		Creator:                 src.Spec.Creator,
+		Data:                    src.Spec.Data,
+		DeletionProtection:      src.Spec.DeletionProtection,
+		Suspended:               src.Spec.Suspended,
+		WorkspaceLocation:       (*v1alpha1.WorkspaceLocation)(src.Spec.Workspace.Location),
+		WorkspaceDeletionPolicy: v1alpha1.WorkspaceDeletionPolicy(src.Spec.Workspace.DeletionPolicy),
+	}
+	if src.Spec.Extensions != nil {
+		dst.Spec.Extensions = make([]v1alpha1.Extension, len(src.Spec.Extensions))
+		for i, extension := range src.Spec.Extensions {
+			dst.Spec.Extensions[i] = v1alpha1.Extension{
+				TypeMeta:           extension.TypeMeta,
+				MetadataGoTemplate: extension.Template.Metadata,
+				SpecGoTemplate:     extension.Template.Spec,
+				ReadyConditionType: extension.ReadyConditionType,
+				DeletionPolicy:     v1alpha1.ExtensionDeletionPolicy(extension.DeletionPolicy),
+				CreateOnly:         extension.CreateOnly,
+			}
+		}
+	}
+
+	dst.Status = v1alpha1.AccountStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		NextReconcileTime:  src.Status.NextReconcileTime,
+		Workspace:          (*v1alpha1.AccountWorkspaceStatus)(src.Status.Workspace),
+		Phase:              v1alpha1.AccountPhase(src.Status.Phase),
+		RestorableUntil:    src.Status.RestorableUntil,
+	}
+	if src.Status.Extensions != nil {
+		dst.Status.Extensions = make([]v1alpha1.ExtensionStatus, len(src.Status.Extensions))
+		for i, status := range src.Status.Extensions {
+			dst.Status.Extensions[i] = v1alpha1.ExtensionStatus{
+				TypeMeta:                status.TypeMeta,
+				Name:                    status.Name,
+				Namespace:               status.Namespace,
+				Phase:                   v1alpha1.ExtensionPhase(status.Phase),
+				DeletionPolicy:          v1alpha1.ExtensionDeletionPolicy(status.DeletionPolicy),
+				LastError:               status.LastError,
+				ReadyCondition:          (*v1alpha1.ObservedCondition)(status.ReadyCondition),
+				LastDriftCorrectionTime: status.LastDriftCorrectionTime,
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Account.
+func (dst *Account) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Account)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = AccountSpec{
+		Type:               AccountType(src.Spec.Type),
+		DisplayName:        src.Spec.DisplayName,
+		Description:        src.Spec.Description,
+		Creator:            src.Spec.Creator,
+		Data:               src.Spec.Data,
+		DeletionProtection: src.Spec.DeletionProtection,
+		Suspended:          src.Spec.Suspended,
+		Workspace: AccountWorkspace{
+			Location:       (*WorkspaceLocation)(src.Spec.WorkspaceLocation),
+			DeletionPolicy: WorkspaceDeletionPolicy(src.Spec.WorkspaceDeletionPolicy),
+		},
+	}
+	if src.Spec.Extensions != nil {
+		dst.Spec.Extensions = make([]Extension, len(src.Spec.Extensions))
+		for i, extension := range src.Spec.Extensions {
+			dst.Spec.Extensions[i] = Extension{
+				TypeMeta: extension.TypeMeta,
+				Template: ExtensionTemplate{
+					Metadata: extension.MetadataGoTemplate,
+					Spec:     extension.SpecGoTemplate,
+				},
+				ReadyConditionType: extension.ReadyConditionType,
+				DeletionPolicy:     ExtensionDeletionPolicy(extension.DeletionPolicy),
+				CreateOnly:         extension.CreateOnly,
+			}
+		}
+	}
+
+	dst.Status = AccountStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		NextReconcileTime:  src.Status.NextReconcileTime,
+		Workspace:          (*AccountWorkspaceStatus)(src.Status.Workspace),
+		Phase:              AccountPhase(src.Status.Phase),
+		RestorableUntil:    src.Status.RestorableUntil,
+	}
+	if src.Status.Extensions != nil {
+		dst.Status.Extensions = make([]ExtensionStatus, len(src.Status.Extensions))
+		for i, status := range src.Status.Extensions {
+			dst.Status.Extensions[i] = ExtensionStatus{
+				TypeMeta:                status.TypeMeta,
+				Name:                    status.Name,
+				Namespace:               status.Namespace,
+				Phase:                   ExtensionPhase(status.Phase),
+				DeletionPolicy:          ExtensionDeletionPolicy(status.DeletionPolicy),
+				LastError:               status.LastError,
+				ReadyCondition:          (*ObservedCondition)(status.ReadyCondition),
+				LastDriftCorrectionTime: status.LastDriftCorrectionTime,
+			}
+		}
+	}
+	return nil
+}
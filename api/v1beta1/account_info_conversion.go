@@ -0,0 +1,79 @@
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &AccountInfo{}
+
+// ConvertTo converts this AccountInfo to the hub version.
+func (src *AccountInfo) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.AccountInfo)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = v1alpha1.AccountInfoSpec{
+		FGA:          v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: src.Spec.FGA.Store.ID}},
+		Account:      locationToHub(src.Spec.Account),
+		Organization: locationToHub(src.Spec.Organization),
+		ClusterInfo:  v1alpha1.ClusterInfo{CA: src.Spec.ClusterInfo.CA},
+	}
+	if src.Spec.ParentAccount != nil {
+		parent := locationToHub(*src.Spec.ParentAccount)
+		dst.Spec.ParentAccount = &parent
+	}
+
+	dst.Status = v1alpha1.AccountInfoStatus(src.Status)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this AccountInfo.
+func (dst *AccountInfo) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.AccountInfo)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = AccountInfoSpec{
+		FGA:          FGAInfo{Store: StoreInfo{ID: src.Spec.FGA.Store.Id}},
+		Account:      locationFromHub(src.Spec.Account),
+		Organization: locationFromHub(src.Spec.Organization),
+		ClusterInfo:  ClusterInfo{CA: src.Spec.ClusterInfo.CA},
+	}
+	if src.Spec.ParentAccount != nil {
+		parent := locationFromHub(*src.Spec.ParentAccount)
+		dst.Spec.ParentAccount = &parent
+	}
+
+	dst.Status = AccountInfoStatus(src.Status)
+	return nil
+}
+
+func locationToHub(location AccountLocation) v1alpha1.AccountLocation {
+	return v1alpha1.AccountLocation{
+		Name:               location.Name,
+		GeneratedClusterId: location.ClusterID,
+		OriginClusterId:    location.OriginClusterID,
+		Path:               location.Path,
+		URL:                location.URL,
+		Type:               v1alpha1.AccountType(location.Type),
+		DisplayName:        location.DisplayName,
+		Description:        location.Description,
+		Creator:            location.Creator,
+		Data:               location.Data,
+	}
+}
+
+func locationFromHub(location v1alpha1.AccountLocation) AccountLocation {
+	return AccountLocation{
+		Name:            location.Name,
+		ClusterID:       location.GeneratedClusterId,
+		OriginClusterID: location.OriginClusterId,
+		Path:            location.Path,
+		URL:             location.URL,
+		Type:            AccountType(location.Type),
+		DisplayName:     location.DisplayName,
+		Description:     location.Description,
+		Creator:         location.Creator,
+		Data:            location.Data,
+	}
+}
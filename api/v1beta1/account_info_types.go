@@ -0,0 +1,102 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccountInfoSpec defines the desired state of Account
+type AccountInfoSpec struct {
+	FGA           FGAInfo          `json:"fga"`
+	Account       AccountLocation  `json:"account"`
+	ParentAccount *AccountLocation `json:"parentAccount,omitempty"`
+	Organization  AccountLocation  `json:"organization"`
+	ClusterInfo   ClusterInfo      `json:"clusterInfo"`
+}
+
+type ClusterInfo struct {
+	CA string `json:"ca"`
+}
+
+type AccountLocation struct {
+	Name string `json:"name"`
+	// The ClusterID of the workspace that was generated for the account
+	ClusterID string `json:"clusterID"`
+	// The OriginClusterID of the workspace that holds the account resource that lead to this workspace
+	OriginClusterID string      `json:"originClusterID"`
+	Path            string      `json:"path"`
+	URL             string      `json:"url"`
+	Type            AccountType `json:"type"`
+
+	// The DisplayName of the account
+	DisplayName string `json:"displayName,omitempty"`
+	// The Description of the account
+	Description *string `json:"description,omitempty"`
+	// The Creator of the account, only set for the account the AccountInfo belongs to
+	Creator *string `json:"creator,omitempty"`
+	// The Data of the account, only set for the account the AccountInfo belongs to
+	Data *apiextensionsv1.JSON `json:"data,omitempty"`
+}
+
+type FGAInfo struct {
+	Store StoreInfo `json:"store"`
+}
+
+type StoreInfo struct {
+	ID string `json:"id"`
+}
+
+// AccountInfoStatus defines the observed state of AccountInfo
+type AccountInfoStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the Account the AccountInfo was last synced from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The time of the last successful sync from the Account
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// The version of the operator that last synced the AccountInfo
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=accountinfos
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Store Assigned",type=string,JSONPath=`.status.conditions[?(@.type=="StoreAssigned")].status`
+// AccountInfo is the Schema for the accountinfo API
+type AccountInfo struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountInfoSpec   `json:"spec,omitempty"`
+	Status AccountInfoStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// AccountInfoList contains a list of AccountInfos
+type AccountInfoList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountInfo `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountInfo{}, &AccountInfoList{})
+}
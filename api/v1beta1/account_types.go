@@ -0,0 +1,255 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AccountType string
+
+const (
+	AccountTypeOrg     AccountType = "org"
+	AccountTypeAccount AccountType = "account"
+)
+
+// AccountSpec defines the desired state of Account
+type AccountSpec struct {
+	// Type specifies the intended type for this Account object. The supported types are configured in the operator,
+	// by default org and account.
+	Type AccountType `json:"type"`
+
+	// The display name for this account
+	// +kubebuilder:validation:MaxLength=255
+	DisplayName string `json:"displayName"`
+
+	// An optional description for this account
+	Description *string `json:"description,omitempty"`
+
+	// The initial creator of this account
+	Creator *string `json:"creator,omitempty"`
+
+	Extensions []Extension `json:"extensions,omitempty"`
+
+	// Additional information that should be stored with the account
+	Data *apiextensionsv1.JSON `json:"data,omitempty"`
+
+	// DeletionProtection denies the deletion of the account until it is set to false again
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// Suspended disables the account without deleting it. The creator and owner tuples of the account are removed
+	// from FGA while the account is suspended and written again once it is resumed.
+	Suspended bool `json:"suspended,omitempty"`
+
+	// Workspace configures the kcp workspace of the account, unset fields use the settings of the account type
+	// +optional
+	Workspace AccountWorkspace `json:"workspace,omitempty"`
+}
+
+// AccountWorkspace configures the kcp workspace of an account
+type AccountWorkspace struct {
+	// Location selects the shard the account workspace is scheduled to, it overrides the location of the account type
+	// and can not be changed once the account exists
+	Location *WorkspaceLocation `json:"location,omitempty"`
+
+	// DeletionPolicy decides whether the account workspace is deleted or kept when the account is deleted, it
+	// overrides the policy of the account type
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy WorkspaceDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// WorkspaceLocation is the location of a kcp workspace
+type WorkspaceLocation struct {
+	// Selector filters the shards the workspace can be scheduled to by their labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// WorkspaceDeletionPolicy describes what happens to the account workspace when its account is deleted
+type WorkspaceDeletionPolicy string
+
+const (
+	// WorkspaceDeletionPolicyDelete deletes the workspace before the account is removed
+	WorkspaceDeletionPolicyDelete WorkspaceDeletionPolicy = "Delete"
+	// WorkspaceDeletionPolicyRetain keeps the workspace, it is detached from the account and labeled as orphaned
+	WorkspaceDeletionPolicyRetain WorkspaceDeletionPolicy = "Retain"
+)
+
+// ExtensionDeletionPolicy describes what happens to an extension object when its account is deleted
+type ExtensionDeletionPolicy string
+
+const (
+	// ExtensionDeletionPolicyDelete removes the extension object before the account is removed
+	ExtensionDeletionPolicyDelete ExtensionDeletionPolicy = "Delete"
+	// ExtensionDeletionPolicyOrphan keeps the extension object when the account is removed
+	ExtensionDeletionPolicyOrphan ExtensionDeletionPolicy = "Orphan"
+)
+
+type Extension struct {
+	metav1.TypeMeta `json:",inline"`
+	// Template is rendered into the extension object
+	Template ExtensionTemplate `json:"template"`
+
+	// The type of a condition that must be set to True on the Extension object
+	// for the extension to be considered reconciled and ready. If this is empty,
+	// the extension is considered ready.
+	ReadyConditionType *string `json:"readyConditionType,omitempty"`
+
+	// DeletionPolicy decides whether the extension object is deleted or kept when the account is deleted.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +kubebuilder:default=Delete
+	DeletionPolicy ExtensionDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// CreateOnly creates the extension object once and leaves it untouched afterwards, for objects that users are
+	// expected to change after creation. Drift is not repaired for create-only extensions.
+	CreateOnly bool `json:"createOnly,omitempty"`
+}
+
+// ExtensionTemplate holds the go templates of an extension object. String values are go templates, see the templating
+// package for the available data and functions.
+type ExtensionTemplate struct {
+	// Metadata is rendered into the metadata of the extension object
+	Metadata apiextensionsv1.JSON `json:"metadata,omitempty"`
+	// Spec is rendered into the spec of the extension object
+	Spec apiextensionsv1.JSON `json:"spec"`
+}
+
+// ExtensionPhase describes how far an extension object got in its lifecycle
+type ExtensionPhase string
+
+const (
+	// ExtensionPhasePending means the extension object was not applied yet
+	ExtensionPhasePending ExtensionPhase = "Pending"
+	// ExtensionPhaseApplied means the extension object exists but its ready condition is not True yet
+	ExtensionPhaseApplied ExtensionPhase = "Applied"
+	// ExtensionPhaseReady means the extension object exists and is ready
+	ExtensionPhaseReady ExtensionPhase = "Ready"
+	// ExtensionPhaseFailed means the extension could not be rendered or applied
+	ExtensionPhaseFailed ExtensionPhase = "Failed"
+	// ExtensionPhaseRemoved means the extension object is no longer rendered by the extensions of the account, it is
+	// deleted according to its deletion policy
+	ExtensionPhaseRemoved ExtensionPhase = "Removed"
+)
+
+// AccountPhase describes the lifecycle stage of an account
+type AccountPhase string
+
+const (
+	// AccountPhaseDeleted means the account was deleted and is kept until status.restorableUntil, it can be
+	// restored with the restore annotation until then
+	AccountPhaseDeleted AccountPhase = "Deleted"
+	// AccountPhaseSuspended means the access granted to the creator of the account is revoked until spec.suspended
+	// is unset
+	AccountPhaseSuspended AccountPhase = "Suspended"
+	// AccountPhaseFailed means the account workspace did not become ready within the provisioning timeout
+	AccountPhaseFailed AccountPhase = "Failed"
+)
+
+// ExtensionStatus reports the observed state of the object rendered from a single extension
+type ExtensionStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The name of the rendered extension object
+	Name string `json:"name,omitempty"`
+	// The namespace of the rendered extension object, empty for cluster scoped objects
+	Namespace string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:Enum=Pending;Applied;Ready;Failed;Removed
+	Phase ExtensionPhase `json:"phase"`
+
+	// The deletion policy of the extension the object was applied for
+	DeletionPolicy ExtensionDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// The last error that occurred while rendering or applying the extension
+	LastError string `json:"lastError,omitempty"`
+
+	// The ready condition as observed on the extension object
+	ReadyCondition *ObservedCondition `json:"readyCondition,omitempty"`
+
+	// The last time a manual change of a managed field was detected and reverted
+	LastDriftCorrectionTime *metav1.Time `json:"lastDriftCorrectionTime,omitempty"`
+}
+
+// ObservedCondition is a condition copied from an object that is not managed by this operator
+type ObservedCondition struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// AccountWorkspaceStatus reports the observed state of the kcp workspace of an account
+type AccountWorkspaceStatus struct {
+	// The phase of the workspace, like Scheduling, Initializing or Ready
+	Phase string `json:"phase,omitempty"`
+	// The logical cluster of the workspace
+	ClusterID string `json:"clusterId,omitempty"`
+	// The URL of the workspace
+	URL string `json:"url,omitempty"`
+}
+
+// AccountStatus defines the observed state of Account
+type AccountStatus struct {
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty" protobuf:"varint,3,opt,name=observedGeneration"`
+	NextReconcileTime  metav1.Time        `json:"nextReconcileTime,omitempty"`
+
+	// The state of the objects rendered from the account extensions, in declaration order, followed by the objects of
+	// removed extensions that are not deleted yet
+	Extensions []ExtensionStatus `json:"extensions,omitempty"`
+
+	// The observed state of the account workspace
+	Workspace *AccountWorkspaceStatus `json:"workspace,omitempty"`
+
+	// The lifecycle stage of the account, empty for active accounts
+	// +kubebuilder:validation:Enum=Deleted;Suspended;Failed
+	Phase AccountPhase `json:"phase,omitempty"`
+
+	// The time until which a deleted account can be restored
+	RestorableUntil *metav1.Time `json:"restorableUntil,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:JSONPath=".spec.displayName",name="Display Name",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.type",name="Type",type=string
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type=string
+
+// Account is the Schema for the accounts API
+type Account struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountSpec   `json:"spec,omitempty"`
+	Status AccountStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AccountList contains a list of Account
+type AccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Account `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Account{}, &AccountList{})
+}
@@ -0,0 +1,143 @@
+package v1beta1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	"sigs.k8s.io/randfill"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/api/v1beta1"
+)
+
+const roundTrips = 200
+
+func TestIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	for _, obj := range []runtime.Object{&v1alpha1.Account{}, &v1alpha1.AccountInfo{}} {
+		convertible, err := conversion.IsConvertible(scheme, obj)
+		require.NoError(t, err)
+		assert.True(t, convertible)
+	}
+}
+
+func TestAccountConversion_RoundTrip(t *testing.T) {
+	filler := randfill.NewWithSeed(1).NilChance(0.2)
+
+	t.Run("hub to spoke to hub", func(t *testing.T) {
+		for range roundTrips {
+			hub := &v1alpha1.Account{}
+			filler.Fill(hub)
+			hub.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &v1beta1.Account{}
+			require.NoError(t, spoke.ConvertFrom(hub))
+			actual := &v1alpha1.Account{}
+			require.NoError(t, spoke.ConvertTo(actual))
+			assert.Equal(t, hub, actual)
+		}
+	})
+
+	t.Run("spoke to hub to spoke", func(t *testing.T) {
+		for range roundTrips {
+			spoke := &v1beta1.Account{}
+			filler.Fill(spoke)
+			spoke.TypeMeta = metav1.TypeMeta{}
+
+			hub := &v1alpha1.Account{}
+			require.NoError(t, spoke.ConvertTo(hub))
+			actual := &v1beta1.Account{}
+			require.NoError(t, actual.ConvertFrom(hub))
+			assert.Equal(t, spoke, actual)
+		}
+	})
+}
+
+func TestAccountInfoConversion_RoundTrip(t *testing.T) {
+	filler := randfill.NewWithSeed(1).NilChance(0.2)
+
+	t.Run("hub to spoke to hub", func(t *testing.T) {
+		for range roundTrips {
+			hub := &v1alpha1.AccountInfo{}
+			filler.Fill(hub)
+			hub.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &v1beta1.AccountInfo{}
+			require.NoError(t, spoke.ConvertFrom(hub))
+			actual := &v1alpha1.AccountInfo{}
+			require.NoError(t, spoke.ConvertTo(actual))
+			assert.Equal(t, hub, actual)
+		}
+	})
+
+	t.Run("spoke to hub to spoke", func(t *testing.T) {
+		for range roundTrips {
+			spoke := &v1beta1.AccountInfo{}
+			filler.Fill(spoke)
+			spoke.TypeMeta = metav1.TypeMeta{}
+
+			hub := &v1alpha1.AccountInfo{}
+			require.NoError(t, spoke.ConvertTo(hub))
+			actual := &v1beta1.AccountInfo{}
+			require.NoError(t, actual.ConvertFrom(hub))
+			assert.Equal(t, spoke, actual)
+		}
+	})
+}
+
+func TestAccountInfoConversion_Fields(t *testing.T) {
+	hub := &v1alpha1.AccountInfo{
+		ObjectMeta: metav1.ObjectMeta{Name: "account"},
+		Spec: v1alpha1.AccountInfoSpec{
+			FGA: v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "store"}},
+			Account: v1alpha1.AccountLocation{
+				Name:               "example",
+				GeneratedClusterId: "generated",
+				OriginClusterId:    "origin",
+				Type:               v1alpha1.AccountTypeAccount,
+				Description:        ptr.To("description"),
+			},
+		},
+	}
+
+	spoke := &v1beta1.AccountInfo{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, "store", spoke.Spec.FGA.Store.ID)
+	assert.Equal(t, "generated", spoke.Spec.Account.ClusterID)
+	assert.Equal(t, "origin", spoke.Spec.Account.OriginClusterID)
+	assert.Equal(t, v1beta1.AccountTypeAccount, spoke.Spec.Account.Type)
+	assert.Equal(t, "description", *spoke.Spec.Account.Description)
+	assert.Nil(t, spoke.Spec.ParentAccount)
+}
+
+func TestAccountConversion_Fields(t *testing.T) {
+	hub := &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "account"},
+		Spec: v1alpha1.AccountSpec{
+			Type:                    v1alpha1.AccountTypeAccount,
+			WorkspaceLocation:       &v1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}},
+			WorkspaceDeletionPolicy: v1alpha1.WorkspaceDeletionPolicyRetain,
+			Extensions: []v1alpha1.Extension{{
+				MetadataGoTemplate: apiextensionsv1.JSON{Raw: []byte(`{"name":"extension"}`)},
+				SpecGoTemplate:     apiextensionsv1.JSON{Raw: []byte(`{"key":"value"}`)},
+			}},
+		},
+	}
+
+	spoke := &v1beta1.Account{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, "eu", spoke.Spec.Workspace.Location.Selector.MatchLabels["region"])
+	assert.Equal(t, v1beta1.WorkspaceDeletionPolicyRetain, spoke.Spec.Workspace.DeletionPolicy)
+	require.Len(t, spoke.Spec.Extensions, 1)
+	assert.JSONEq(t, `{"name":"extension"}`, string(spoke.Spec.Extensions[0].Template.Metadata.Raw))
+	assert.JSONEq(t, `{"key":"value"}`, string(spoke.Spec.Extensions[0].Template.Spec.Raw))
+}
@@ -19,7 +19,9 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/http"
+	"strings"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
@@ -29,19 +31,27 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"github.com/openmfp/account-operator/api/v1alpha1"
 	"github.com/openmfp/account-operator/internal/controller"
+	"github.com/openmfp/account-operator/pkg/dataschema"
+	"github.com/openmfp/account-operator/pkg/subroutines"
 )
 
 var operatorCmd = &cobra.Command{
@@ -116,6 +126,10 @@ func RunController(_ *cobra.Command, _ []string) { // coverage-ignore
 		LeaderElectionConfig:          restCfg,
 		LeaderElectionReleaseOnCancel: true,
 	}
+	if operatorCfg.Subroutines.Extension.Enabled {
+		// extension objects are only checked for drift when their account is reconciled
+		opts.Cache = cache.Options{SyncPeriod: &operatorCfg.Subroutines.Extension.ResyncPeriod}
+	}
 	var mgr ctrl.Manager
 	mgrConfig := rest.CopyConfig(restCfg)
 	if len(operatorCfg.Kcp.ApiExportEndpointSliceName) > 0 {
@@ -158,15 +172,78 @@ func RunController(_ *cobra.Command, _ []string) { // coverage-ignore
 		fgaClient = openfgav1.NewOpenFGAServiceClient(conn)
 	}
 
-	accountReconciler := controller.NewAccountReconciler(log, mgr, operatorCfg, fgaClient)
+	dataSchemas, err := loadDataSchemas(ctx, restCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to load data schemas")
+	}
+
+	var accountTypes *v1alpha1.AccountTypeRegistry
+	if operatorCfg.AccountTypes.File != "" {
+		accountTypes, err = v1alpha1.LoadAccountTypesFile(operatorCfg.AccountTypes.File)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to load account types")
+		}
+	}
+
+	depthLimits, err := v1alpha1.ParseDepthLimits(operatorCfg.Hierarchy.MaxDepth, operatorCfg.Hierarchy.MaxDepthOverrides)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid hierarchy depth configuration")
+	}
+	childAccountLimits, err := v1alpha1.ParseChildAccountLimits(operatorCfg.Quota.MaxChildAccountsPerOrg, operatorCfg.Quota.MaxChildAccountsPerAccount,
+		operatorCfg.Quota.MaxChildAccountsPerOrgOverrides, operatorCfg.Quota.MaxChildAccountsPerAccountOverrides)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid quota configuration")
+	}
+
+	if operatorCfg.Kcp.WorkspaceShardSelector != "" {
+		if _, err := metav1.ParseToLabelSelector(operatorCfg.Kcp.WorkspaceShardSelector); err != nil {
+			log.Fatal().Err(err).Msg("invalid workspace shard selector")
+		}
+	}
+
+	accountReconciler := controller.NewAccountReconciler(log, mgr, operatorCfg, fgaClient, dataSchemas, depthLimits, accountTypes)
 	if err := accountReconciler.SetupWithManager(mgr, defaultCfg, log); err != nil {
 		log.Fatal().Err(err).Str("controller", "Account").Msg("unable to create controller")
 	}
 
+	if operatorCfg.Trash.RetentionPeriod > 0 {
+		if err := mgr.Add(subroutines.NewTrashSweeper(mgr.GetClient(), operatorCfg.Trash.SweepInterval, log)); err != nil {
+			log.Fatal().Err(err).Msg("unable to add the trash sweeper")
+		}
+	}
+
+	if operatorCfg.AccountMove.Enabled {
+		// the SubjectAccessReviews for the creators of moves are made against kcp directly
+		authorizer, err := kcp.NewClusterAwareClient(restCfg, client.Options{Scheme: scheme})
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to create the authorization client")
+		}
+		accountMoveReconciler := controller.NewAccountMoveReconciler(log, mgr, operatorCfg, authorizer, fgaClient, depthLimits, accountTypes)
+		if err := accountMoveReconciler.SetupWithManager(mgr, defaultCfg, log); err != nil {
+			log.Fatal().Err(err).Str("controller", "AccountMove").Msg("unable to create controller")
+		}
+	}
+
+	// the CRDs convert between their served versions with the webhook, it is served even without the admission webhooks
+	mgr.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(mgr.GetScheme()))
+
 	if operatorCfg.Webhooks.Enabled {
-		if err := v1alpha1.SetupAccountWebhookWithManager(mgr); err != nil {
+		creatorAuthorizer := newCreatorAuthorizer(ctx, restCfg)
+		accountValidator := &v1alpha1.AccountValidator{
+			DataSchemas:                 dataSchemas,
+			DepthLimits:                 depthLimits,
+			AccountTypes:                accountTypes,
+			ProtectAccountsWithChildren: operatorCfg.Webhooks.ProtectAccountsWithChildren,
+			ChildAccountLimits:          childAccountLimits,
+		}
+		if err := v1alpha1.SetupAccountWebhookWithManager(mgr, &v1alpha1.AccountDefaulter{AccountTypes: accountTypes, CreatorAuthorizer: creatorAuthorizer}, accountValidator); err != nil {
 			log.Fatal().Err(err).Str("webhook", "Account").Msg("unable to create webhook")
 		}
+		if operatorCfg.AccountMove.Enabled {
+			if err := v1alpha1.SetupAccountMoveWebhookWithManager(mgr); err != nil {
+				log.Fatal().Err(err).Str("webhook", "AccountMove").Msg("unable to create webhook")
+			}
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -181,3 +258,62 @@ func RunController(_ *cobra.Command, _ []string) { // coverage-ignore
 		log.Fatal().Err(err).Msg("problem running manager")
 	}
 }
+
+// newCreatorAuthorizer creates the authorizer for callers that set the creator of a new account. Without the
+// creator override only the operator may set the creator. The SubjectAccessReviews are made against kcp directly, the
+// APIExport virtual workspace does not serve them.
+func newCreatorAuthorizer(ctx context.Context, restCfg *rest.Config) *v1alpha1.CreatorAuthorizer { // coverage-ignore
+	creatorAuthorizer := &v1alpha1.CreatorAuthorizer{Operator: operatorUsername(ctx, restCfg)}
+	if operatorCfg.Webhooks.CreatorOverrideVerb == "" {
+		return creatorAuthorizer
+	}
+
+	authorizer, err := kcp.NewClusterAwareClient(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to create the authorization client")
+	}
+	resource, subresource, _ := strings.Cut(operatorCfg.Webhooks.CreatorOverrideResource, "/")
+	creatorAuthorizer.Client = authorizer
+	creatorAuthorizer.Permission = authorizationv1.ResourceAttributes{
+		Verb:        operatorCfg.Webhooks.CreatorOverrideVerb,
+		Group:       v1alpha1.GroupVersion.Group,
+		Resource:    resource,
+		Subresource: subresource,
+	}
+	return creatorAuthorizer
+}
+
+// operatorUsername looks up the user the operator authenticates as, empty if kcp does not tell. Restored accounts then
+// get the operator as creator.
+func operatorUsername(ctx context.Context, restCfg *rest.Config) string { // coverage-ignore
+	kclient, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to create client")
+	}
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := kclient.Create(ctx, review); err != nil {
+		log.Warn().Err(err).Msg("unable to look up the user of the operator, restored accounts do not keep their creator")
+		return ""
+	}
+	return review.Status.UserInfo.Username
+}
+
+// loadDataSchemas loads the schemas of spec.data from the configured file or ConfigMap, nil if none is configured.
+func loadDataSchemas(ctx context.Context, restCfg *rest.Config) (*dataschema.Registry, error) { // coverage-ignore
+	if operatorCfg.DataSchemas.File != "" {
+		return dataschema.LoadFile(operatorCfg.DataSchemas.File)
+	}
+	if operatorCfg.DataSchemas.ConfigMap == "" {
+		return nil, nil
+	}
+
+	namespace, name, found := strings.Cut(operatorCfg.DataSchemas.ConfigMap, "/")
+	if !found {
+		return nil, fmt.Errorf("data schemas ConfigMap %q is not in the format namespace/name", operatorCfg.DataSchemas.ConfigMap)
+	}
+	kclient, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	return dataschema.LoadConfigMap(ctx, kclient, types.NamespacedName{Namespace: namespace, Name: name})
+}
@@ -0,0 +1,67 @@
+#!/usr/bin/env bash
+
+# Sets the conversion webhook of CustomResourceDefinitions and APIResourceSchemas that serve more than one version.
+# controller-gen does not generate spec.conversion, but apigen requires it to build the APIResourceSchemas. Deployments
+# run the script on the generated files to point the conversion to their own webhook endpoint and CA, an existing
+# spec.conversion is replaced.
+#
+# Usage: hack/crd-conversion.sh [-u url] [-c ca-file] [-i namespace/certificate] <file>...
+#
+#   -u  URL of the conversion webhook, kcp does not support service references. Defaults to $CONVERSION_WEBHOOK_URL or
+#       the account-operator-webhook service in the openmfp-system namespace.
+#   -c  PEM file with the CA of the webhook certificate, set as caBundle. Defaults to $CONVERSION_WEBHOOK_CA_FILE.
+#   -i  cert-manager Certificate as namespace/name whose CA the cert-manager CA injector sets as caBundle of the
+#       CustomResourceDefinitions. Defaults to $CONVERSION_WEBHOOK_INJECT_CA_FROM.
+
+set -euo pipefail
+
+url="${CONVERSION_WEBHOOK_URL:-https://account-operator-webhook.openmfp-system.svc:9443/convert}"
+ca_file="${CONVERSION_WEBHOOK_CA_FILE:-}"
+inject_ca_from="${CONVERSION_WEBHOOK_INJECT_CA_FROM:-}"
+
+while getopts "u:c:i:" opt; do
+  case "${opt}" in
+    u) url="${OPTARG}" ;;
+    c) ca_file="${OPTARG}" ;;
+    i) inject_ca_from="${OPTARG}" ;;
+    *) echo "usage: $0 [-u url] [-c ca-file] [-i namespace/certificate] <file>..." >&2; exit 1 ;;
+  esac
+done
+shift $((OPTIND - 1))
+
+ca_bundle=""
+if [[ -n "${ca_file}" ]]; then
+  ca_bundle="$(base64 < "${ca_file}" | tr -d '\n')"
+fi
+
+for file in "$@"; do
+  crd=0
+  if grep -q '^kind: CustomResourceDefinition$' "${file}"; then
+    crd=1
+  fi
+  awk -v url="${url}" -v ca_bundle="${ca_bundle}" -v inject="${inject_ca_from}" -v crd="${crd}" '
+    # the existing conversion and CA injection are replaced
+    in_conversion && /^    / { next }
+    { in_conversion = 0 }
+    /^  conversion:$/ { in_conversion = 1; next }
+    /^    cert-manager.io\/inject-ca-from:/ { next }
+
+    { print }
+    /^  annotations:$/ && section == "metadata" && crd && inject != "" {
+      print "    cert-manager.io/inject-ca-from: " inject
+    }
+    /^[a-z]+:/ { section = substr($1, 1, length($1) - 1) }
+    /^spec:$/ && !done {
+      print "  conversion:"
+      print "    strategy: Webhook"
+      print "    webhook:"
+      print "      clientConfig:"
+      if (ca_bundle != "") print "        caBundle: " ca_bundle
+      print "        url: " url
+      print "      conversionReviewVersions:"
+      print "      - v1"
+      done = 1
+    }
+  ' "${file}" > "${file}.tmp"
+  mv "${file}.tmp" "${file}"
+done
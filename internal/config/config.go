@@ -1,15 +1,26 @@
 package config
 
+import "time"
+
 // OperatorConfig struct to hold the app config
 type OperatorConfig struct {
 	Webhooks struct {
 		Enabled bool   `mapstructure:"webhooks-enabled" default:"false"`
 		CertDir string `mapstructure:"webhooks-cert-dir" default:"certs"`
 		Port    int    `mapstructure:"webhooks-port" default:"9443"`
+		// CreatorOverrideVerb and CreatorOverrideResource are the permission a caller needs in the workspace of an
+		// account to set its creator to someone else
+		CreatorOverrideVerb         string `mapstructure:"webhooks-creator-override-verb" default:"create" description:"Verb a caller needs on the creator override resource to set the creator of a new account, empty to always use the caller as creator of new accounts, except for accounts the operator restores"`
+		CreatorOverrideResource     string `mapstructure:"webhooks-creator-override-resource" default:"accounts/creator" description:"Resource of the core.openmfp.org group, optionally with a subresource, a caller needs the creator override verb on to set the creator of a new account"`
+		ProtectAccountsWithChildren bool   `mapstructure:"webhooks-protect-accounts-with-children" default:"false" description:"Denies the deletion of accounts whose workspace still contains accounts"`
 	} `mapstructure:",squash"`
 	Subroutines struct {
 		Workspace struct {
-			Enabled bool `mapstructure:"subroutines-workspace-enabled" default:"true"`
+			Enabled                      bool          `mapstructure:"subroutines-workspace-enabled" default:"true"`
+			CascadingDeletion            bool          `mapstructure:"subroutines-workspace-cascading-deletion" default:"false" description:"Deletes the accounts in the workspace of a deleted account and waits for them to be finalized before the workspace is deleted"`
+			ProvisioningTimeout          time.Duration `mapstructure:"subroutines-workspace-provisioning-timeout" default:"10m" description:"Time a new account workspace may take to become ready before the account is marked as failed, 0 disables the timeout"`
+			PropagatedLabelPrefixes      string        `mapstructure:"subroutines-workspace-propagated-label-prefixes" description:"Comma separated list of label key prefixes that are copied from accounts to their workspace"`
+			PropagatedAnnotationPrefixes string        `mapstructure:"subroutines-workspace-propagated-annotation-prefixes" description:"Comma separated list of annotation key prefixes that are copied from accounts to their workspace"`
 		} `mapstructure:",squash"`
 		AccountInfo struct {
 			Enabled bool `mapstructure:"subroutines-account-info-enabled" default:"true"`
@@ -22,9 +33,38 @@ type OperatorConfig struct {
 			ParentRelation  string `mapstructure:"subroutines-fga-parent-relation" default:"parent"`
 			CreatorRelation string `mapstructure:"subroutines-fga-creator-relation" default:"owner"`
 		} `mapstructure:",squash"`
+		Extension struct {
+			Enabled      bool          `mapstructure:"subroutines-extension-enabled" default:"false"`
+			ResyncPeriod time.Duration `mapstructure:"subroutines-extension-resync-period" default:"10m" description:"Interval in which all accounts are reconciled to repair drift of extension objects"`
+		} `mapstructure:",squash"`
+	} `mapstructure:",squash"`
+	Trash struct {
+		RetentionPeriod time.Duration `mapstructure:"trash-retention-period" default:"0s" description:"Time deleted accounts are kept in the Deleted phase with their workspace and can be restored, 0 deletes accounts right away"`
+		SweepInterval   time.Duration `mapstructure:"trash-sweep-interval" default:"5m" description:"Interval in which deleted accounts whose retention period expired are finalized"`
+	} `mapstructure:",squash"`
+	AccountMove struct {
+		Enabled bool `mapstructure:"account-move-enabled" default:"true" description:"Enables moving accounts to a different parent with AccountMove resources"`
+	} `mapstructure:",squash"`
+	Hierarchy struct {
+		MaxDepth          int    `mapstructure:"hierarchy-max-depth" default:"0" description:"Maximum depth of accounts below their organization, accounts in the organization have a depth of 1, 0 disables the limit"`
+		MaxDepthOverrides string `mapstructure:"hierarchy-max-depth-overrides" description:"Comma separated list of organization=depth pairs that override the maximum depth per organization"`
+	} `mapstructure:",squash"`
+	Quota struct {
+		MaxChildAccountsPerOrg              int    `mapstructure:"quota-max-child-accounts-per-org" default:"0" description:"Maximum number of accounts in an organization workspace, 0 disables the limit"`
+		MaxChildAccountsPerAccount          int    `mapstructure:"quota-max-child-accounts-per-account" default:"0" description:"Maximum number of accounts in an account workspace, 0 disables the limit"`
+		MaxChildAccountsPerOrgOverrides     string `mapstructure:"quota-max-child-accounts-per-org-overrides" description:"Comma separated list of organization=count pairs that override the maximum number of accounts in the workspace of the organization, 0 allows no accounts"`
+		MaxChildAccountsPerAccountOverrides string `mapstructure:"quota-max-child-accounts-per-account-overrides" description:"Comma separated list of organization=count pairs that override the maximum number of accounts in the account workspaces of the organization, 0 allows no accounts"`
+	} `mapstructure:",squash"`
+	AccountTypes struct {
+		File string `mapstructure:"account-types-file" description:"File with the account type definitions, by default the types org and account are supported"`
+	} `mapstructure:",squash"`
+	DataSchemas struct {
+		File      string `mapstructure:"data-schemas-file" description:"File with the JSON schemas of spec.data per account type"`
+		ConfigMap string `mapstructure:"data-schemas-configmap" description:"ConfigMap with the JSON schemas of spec.data per account type, as namespace/name"`
 	} `mapstructure:",squash"`
 	Kcp struct {
 		ApiExportEndpointSliceName string `mapstructure:"kcp-api-export-endpoint-slice-name"`
 		ProviderWorkspace          string `mapstructure:"kcp-provider-workspace" default:"root"`
+		WorkspaceShardSelector     string `mapstructure:"kcp-workspace-shard-selector" description:"Label selector of the shards account workspaces are scheduled to, unless the account or its type sets a workspace location. Type and location are the only fields of the Workspace spec the operator sets, kcp assigns the cluster and URL"`
 	} `mapstructure:",squash"`
 }
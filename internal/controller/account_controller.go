@@ -19,17 +19,23 @@ package controller
 import (
 	"context"
 
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
 	openmfpconfig "github.com/platform-mesh/golang-commons/config"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/controllerruntime"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	"github.com/platform-mesh/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	corev1alpha1 "github.com/openmfp/account-operator/api/v1alpha1"
 	"github.com/openmfp/account-operator/internal/config"
+	"github.com/openmfp/account-operator/pkg/dataschema"
 	"github.com/openmfp/account-operator/pkg/subroutines"
 )
 
@@ -40,27 +46,64 @@ var (
 
 // AccountReconciler reconciles a Account object
 type AccountReconciler struct {
+	client    client.Client
 	lifecycle *controllerruntime.LifecycleManager
 }
 
-func NewAccountReconciler(log *logger.Logger, mgr ctrl.Manager, cfg config.OperatorConfig, fgaClient openfgav1.OpenFGAServiceClient) *AccountReconciler {
+func NewAccountReconciler(log *logger.Logger, mgr ctrl.Manager, cfg config.OperatorConfig, fgaClient openfgav1.OpenFGAServiceClient, dataSchemas *dataschema.Registry, depthLimits corev1alpha1.DepthLimits, accountTypes *corev1alpha1.AccountTypeRegistry) *AccountReconciler {
 	var subs []subroutine.Subroutine
+	if dataSchemas != nil {
+		subs = append(subs, subroutines.NewDataValidationSubroutine(dataSchemas))
+	}
+	if depthLimits.Enabled() {
+		subs = append(subs, subroutines.NewHierarchySubroutine(mgr.GetClient(), depthLimits, accountTypes))
+	}
 	if cfg.Subroutines.Workspace.Enabled {
-		subs = append(subs, subroutines.NewWorkspaceSubroutine(mgr.GetClient()))
+		subs = append(subs, subroutines.NewWorkspaceSubroutine(mgr.GetClient(), accountTypes))
 	}
 	if cfg.Subroutines.AccountInfo.Enabled {
-		subs = append(subs, subroutines.NewAccountInfoSubroutine(mgr.GetClient(), string(mgr.GetConfig().CAData)))
+		subs = append(subs, subroutines.NewAccountInfoSubroutine(mgr.GetClient(), string(mgr.GetConfig().CAData), accountTypes))
 	}
 	if cfg.Subroutines.FGA.Enabled {
-		subs = append(subs, subroutines.NewFGASubroutine(mgr.GetClient(), fgaClient, cfg.Subroutines.FGA.CreatorRelation, cfg.Subroutines.FGA.ParentRelation, cfg.Subroutines.FGA.ObjectType))
+		subs = append(subs, subroutines.NewFGASubroutine(mgr.GetClient(), fgaClient, cfg.Subroutines.FGA.CreatorRelation, cfg.Subroutines.FGA.ParentRelation, cfg.Subroutines.FGA.ObjectType, accountTypes))
+	}
+	if cfg.Subroutines.Extension.Enabled {
+		subs = append(subs, subroutines.NewExtensionSubroutine(mgr.GetClient()))
+	}
+	// finalized first, the other finalizers wait until the retention period of a deleted account expired
+	if cfg.Trash.RetentionPeriod > 0 {
+		subs = append(subs, subroutines.NewTrashSubroutine(mgr.GetClient(), cfg.Trash.RetentionPeriod))
 	}
 	return &AccountReconciler{
+		client:    mgr.GetClient(),
 		lifecycle: controllerruntime.NewLifecycleManager(log, operatorName, accountReconcilerName, mgr.GetClient(), subs).WithConditionManagement(),
 	}
 }
 
+// Reconcile runs the lifecycle of the account. The lifecycle sets the Ready condition with a generic reason, if the
+// extensions of the account failed in the reconcile the Ready condition gets their reason and message instead.
 func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.lifecycle.Reconcile(ctx, req, &corev1alpha1.Account{})
+	ctx, extensionFailure := subroutines.WithExtensionFailure(ctx)
+	account := &corev1alpha1.Account{}
+	result, err := r.lifecycle.Reconcile(ctx, req, account)
+	if failure := extensionFailure(); failure != nil {
+		if readyErr := r.setReadyReason(ctx, account, failure); readyErr != nil && err == nil {
+			return result, readyErr
+		}
+	}
+	return result, err
+}
+
+// setReadyReason sets the reason and message of the failure on a Ready condition that is not True
+func (r *AccountReconciler) setReadyReason(ctx context.Context, account *corev1alpha1.Account, failure *metav1.Condition) error {
+	ready := meta.FindStatusCondition(account.Status.Conditions, corev1alpha1.ConditionReady)
+	if ready == nil || ready.Status == metav1.ConditionTrue {
+		return nil
+	}
+	original := account.DeepCopy()
+	ready.Reason = failure.Reason
+	ready.Message = failure.Message
+	return r.client.Status().Patch(ctx, account, client.MergeFrom(original))
 }
 
 func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager, cfg *openmfpconfig.CommonServiceConfig, log *logger.Logger, eventPredicates ...predicate.Predicate) error {
@@ -68,5 +111,7 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager, cfg *openmfpconfi
 	if err != nil {
 		return err
 	}
+	// accounts wait for their workspace to become ready without retrying once the provisioning timeout passed
+	builder.Watches(&kcptenancyv1alpha.Workspace{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &corev1alpha1.Account{}))
 	return builder.Complete(kcp.WithClusterInContext(r))
 }
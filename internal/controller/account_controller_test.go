@@ -114,7 +114,7 @@ func (suite *AccountTestSuite) SetupSuite() {
 	suite.Require().NoError(err)
 
 	mockClient := mocks.NewOpenFGAServiceClient(suite.T())
-	accountReconciler := controller.NewAccountReconciler(log, suite.kubernetesManager, cfg, mockClient)
+	accountReconciler := controller.NewAccountReconciler(log, suite.kubernetesManager, cfg, mockClient, nil, v1alpha1.DepthLimits{}, nil)
 	dCfg := &openmfpconfig.CommonServiceConfig{}
 	err = accountReconciler.SetupWithManager(suite.kubernetesManager, dCfg, log)
 	suite.Require().NoError(err)
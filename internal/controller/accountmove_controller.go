@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openmfpconfig "github.com/platform-mesh/golang-commons/config"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/controllerruntime"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/logger"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kcp"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	corev1alpha1 "github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+)
+
+var accountMoveReconcilerName = "AccountMoveReconciler"
+
+// AccountMoveReconciler reconciles an AccountMove object
+type AccountMoveReconciler struct {
+	lifecycle *controllerruntime.LifecycleManager
+}
+
+func NewAccountMoveReconciler(log *logger.Logger, mgr ctrl.Manager, cfg config.OperatorConfig, authorizer client.Client, fgaClient openfgav1.OpenFGAServiceClient, depthLimits corev1alpha1.DepthLimits, accountTypes *corev1alpha1.AccountTypeRegistry) *AccountMoveReconciler {
+	if !cfg.Subroutines.FGA.Enabled {
+		fgaClient = nil
+	}
+	subs := []subroutine.Subroutine{
+		subroutines.NewAccountMoveSubroutine(mgr.GetClient(), authorizer, fgaClient, cfg.Subroutines.FGA.ObjectType, cfg.Subroutines.FGA.ParentRelation, depthLimits, accountTypes),
+	}
+	return &AccountMoveReconciler{
+		lifecycle: controllerruntime.NewLifecycleManager(log, operatorName, accountMoveReconcilerName, mgr.GetClient(), subs).WithConditionManagement(),
+	}
+}
+
+func (r *AccountMoveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.lifecycle.Reconcile(ctx, req, &corev1alpha1.AccountMove{})
+}
+
+func (r *AccountMoveReconciler) SetupWithManager(mgr ctrl.Manager, cfg *openmfpconfig.CommonServiceConfig, log *logger.Logger, eventPredicates ...predicate.Predicate) error {
+	builder, err := r.lifecycle.SetupWithManagerBuilder(mgr, cfg.MaxConcurrentReconciles, accountMoveReconcilerName, &corev1alpha1.AccountMove{}, cfg.DebugLabelValue, log, eventPredicates...)
+	if err != nil {
+		return err
+	}
+	return builder.Complete(kcp.WithClusterInContext(r))
+}
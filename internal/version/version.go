@@ -0,0 +1,18 @@
+// Package version reports the version of the running operator.
+package version
+
+import "runtime/debug"
+
+// Version is set at build time with -ldflags "-X github.com/openmfp/account-operator/internal/version.Version=<version>"
+var Version = ""
+
+// Get returns the version set at build time. Without it, the module version from the build info is used.
+func Get() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
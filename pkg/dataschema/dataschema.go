@@ -0,0 +1,142 @@
+// Package dataschema validates the free form spec.data of accounts against a JSON schema registered per account type.
+//
+// Schemas are registered as a document that maps the account type to an OpenAPI v3 schema, in the format used for
+// CustomResourceDefinitions:
+//
+//	org:
+//	  type: object
+//	  required: ["costCenter"]
+//	  properties:
+//	    costCenter:
+//	      type: string
+//	account:
+//	  type: object
+//	  additionalProperties: false
+//
+// The document is read from a file or from a ConfigMap with one key per account type.
+package dataschema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Registry holds the compiled schemas by account type. A nil Registry has no schemas.
+type Registry struct {
+	validators map[string]*validate.SchemaValidator
+}
+
+// New compiles the given schemas, keyed by account type.
+func New(schemas map[string]apiextensionsv1.JSONSchemaProps) (*Registry, error) {
+	r := &Registry{validators: make(map[string]*validate.SchemaValidator, len(schemas))}
+	for accountType, props := range schemas {
+		// JSONSchemaProps and the OpenAPI schema share their json representation
+		raw, err := json.Marshal(props)
+		if err != nil {
+			return nil, fmt.Errorf("converting schema for %q: %w", accountType, err)
+		}
+		schema := &spec.Schema{}
+		if err := json.Unmarshal(raw, schema); err != nil {
+			return nil, fmt.Errorf("converting schema for %q: %w", accountType, err)
+		}
+		r.validators[accountType] = validate.NewSchemaValidator(schema, nil, "", strfmt.Default)
+	}
+	return r, nil
+}
+
+// LoadFile reads a yaml or json document that maps account types to schemas.
+func LoadFile(path string) (*Registry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := map[string]apiextensionsv1.JSONSchemaProps{}
+	if err := yaml.UnmarshalStrict(raw, &schemas); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return New(schemas)
+}
+
+// LoadConfigMap reads the schemas from a ConfigMap, every key is an account type and every value a yaml or json
+// schema.
+func LoadConfigMap(ctx context.Context, c client.Reader, key types.NamespacedName) (*Registry, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, key, cm); err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]apiextensionsv1.JSONSchemaProps, len(cm.Data))
+	for accountType, raw := range cm.Data {
+		schema := apiextensionsv1.JSONSchemaProps{}
+		if err := yaml.UnmarshalStrict([]byte(raw), &schema); err != nil {
+			return nil, fmt.Errorf("parsing schema for %q in %s: %w", accountType, key, err)
+		}
+		schemas[accountType] = schema
+	}
+	return New(schemas)
+}
+
+// HasSchema reports whether a schema is registered for the account type.
+func (r *Registry) HasSchema(accountType string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.validators[accountType]
+	return ok
+}
+
+// Validate validates data against the schema of the account type. Accounts without data are validated as an empty
+// object, so required properties are enforced. Types without a schema accept any data.
+func (r *Registry) Validate(path *field.Path, accountType string, data *apiextensionsv1.JSON) field.ErrorList {
+	if !r.HasSchema(accountType) {
+		return nil
+	}
+
+	var value any = map[string]any{}
+	if data != nil && len(data.Raw) > 0 {
+		if err := json.Unmarshal(data.Raw, &value); err != nil {
+			return field.ErrorList{field.Invalid(path, string(data.Raw), err.Error())}
+		}
+	}
+	return toFieldErrors(path, r.validators[accountType].Validate(value))
+}
+
+func toFieldErrors(path *field.Path, result *validate.Result) field.ErrorList {
+	var errs field.ErrorList
+	for _, err := range result.Errors {
+		validationErr, ok := err.(*openapierrors.Validation)
+		if !ok {
+			errs = append(errs, field.Invalid(path, "", err.Error()))
+			continue
+		}
+
+		errPath := path
+		if name := strings.TrimPrefix(validationErr.Name, "."); name != "" {
+			errPath = errPath.Child(name)
+		}
+		switch validationErr.Code() {
+		case openapierrors.RequiredFailCode:
+			errs = append(errs, field.Required(errPath, ""))
+		case openapierrors.InvalidTypeCode:
+			errs = append(errs, field.TypeInvalid(errPath, validationErr.Value, validationErr.Error()))
+		default:
+			errs = append(errs, field.Invalid(errPath, validationErr.Value, validationErr.Error()))
+		}
+	}
+	return errs
+}
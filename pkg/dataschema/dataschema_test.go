@@ -0,0 +1,105 @@
+package dataschema_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/account-operator/pkg/dataschema"
+)
+
+const orgSchema = `
+type: object
+required: ["costCenter"]
+properties:
+  costCenter:
+    type: string
+    pattern: "^cc-[0-9]+$"
+  tier:
+    type: string
+    enum: ["basic", "premium"]
+`
+
+func TestValidate(t *testing.T) {
+	registry := loadTestFile(t, "org:\n  "+strings.ReplaceAll(strings.TrimSpace(orgSchema), "\n", "\n  ")+"\naccount:\n  type: object\n")
+
+	tests := []struct {
+		name        string
+		accountType string
+		data        *apiextensionsv1.JSON
+		fields      []string
+	}{
+		{name: "valid", accountType: "org", data: &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1","tier":"basic"}`)}},
+		{name: "missing data", accountType: "org", fields: []string{"spec.data.costCenter"}},
+		{name: "wrong type", accountType: "org", data: &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":1}`)}, fields: []string{"spec.data.costCenter"}},
+		{name: "pattern", accountType: "org", data: &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc1"}`)}, fields: []string{"spec.data.costCenter"}},
+		{name: "enum", accountType: "org", data: &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1","tier":"gold"}`)}, fields: []string{"spec.data.tier"}},
+		{name: "other type", accountType: "account", data: &apiextensionsv1.JSON{Raw: []byte(`{"anything":true}`)}},
+		{name: "unregistered type", accountType: "project", data: &apiextensionsv1.JSON{Raw: []byte(`"not an object"`)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := registry.Validate(field.NewPath("spec", "data"), test.accountType, test.data)
+			var actual []string
+			for _, err := range errs {
+				actual = append(actual, err.Field)
+			}
+			assert.Equal(t, test.fields, actual)
+		})
+	}
+}
+
+func TestNilRegistry(t *testing.T) {
+	var registry *dataschema.Registry
+	assert.False(t, registry.HasSchema("org"))
+	assert.Empty(t, registry.Validate(field.NewPath("spec", "data"), "org", nil))
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	_, err := dataschema.LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "schemas.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("org:\n  unknownField: true\n"), 0o600))
+	_, err = dataschema.LoadFile(path)
+	assert.Error(t, err)
+}
+
+func TestLoadConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "schemas", Namespace: "openmfp-system"},
+		Data:       map[string]string{"org": orgSchema},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+
+	registry, err := dataschema.LoadConfigMap(context.Background(), c, types.NamespacedName{Name: "schemas", Namespace: "openmfp-system"})
+	require.NoError(t, err)
+	assert.True(t, registry.HasSchema("org"))
+	assert.False(t, registry.HasSchema("account"))
+
+	_, err = dataschema.LoadConfigMap(context.Background(), c, types.NamespacedName{Name: "missing", Namespace: "openmfp-system"})
+	assert.Error(t, err)
+}
+
+func loadTestFile(t *testing.T, content string) *dataschema.Registry {
+	path := filepath.Join(t.TempDir(), "schemas.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	registry, err := dataschema.LoadFile(path)
+	require.NoError(t, err)
+	return registry
+}
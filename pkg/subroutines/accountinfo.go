@@ -13,7 +13,9 @@ import (
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	"github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -22,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
 	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/version"
 )
 
 var _ subroutine.Subroutine = (*AccountInfoSubroutine)(nil)
@@ -32,14 +35,15 @@ const (
 )
 
 type AccountInfoSubroutine struct {
-	client   client.Client
-	serverCA string
-	limiter  workqueue.TypedRateLimiter[ClusteredName]
+	client       client.Client
+	serverCA     string
+	accountTypes *v1alpha1.AccountTypeRegistry
+	limiter      workqueue.TypedRateLimiter[ClusteredName]
 }
 
-func NewAccountInfoSubroutine(client client.Client, serverCA string) *AccountInfoSubroutine {
+func NewAccountInfoSubroutine(client client.Client, serverCA string, accountTypes *v1alpha1.AccountTypeRegistry) *AccountInfoSubroutine {
 	exp := workqueue.NewTypedItemExponentialFailureRateLimiter[ClusteredName](1*time.Second, 120*time.Second)
-	return &AccountInfoSubroutine{client: client, serverCA: serverCA, limiter: exp}
+	return &AccountInfoSubroutine{client: client, serverCA: serverCA, accountTypes: accountTypes, limiter: exp}
 }
 
 func (r *AccountInfoSubroutine) GetName() string {
@@ -74,15 +78,26 @@ func (r *AccountInfoSubroutine) Process(ctx context.Context, ro runtimeobject.Ru
 		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 	}
 
+	// Prepare context to work in workspace
+	wsCtx := kontext.WithCluster(ctx, logicalcluster.Name(accountWorkspace.Spec.Cluster))
+
 	if accountWorkspace.Status.Phase != kcpcorev1alpha.LogicalClusterPhaseReady {
 		log.Info().Msg("workspace is not ready yet, retry")
+		// the workspace may not be reachable before it is ready, a failure to mark the AccountInfo does not matter
+		if accountWorkspace.Spec.Cluster != "" {
+			err = r.markNotSynced(wsCtx, v1.ConditionUnknown, v1alpha1.AccountInfoReasonWorkspaceNotReady, "the account workspace is not ready yet")
+			if err != nil {
+				log.Info().Err(err).Msg("could not mark the AccountInfo as not synced")
+			}
+		}
+		if err := workspaceTimeoutError(ctx, accountWorkspace); err != nil {
+			r.limiter.Forget(cn)
+			return ctrl.Result{}, errors.NewOperatorError(err, false, false)
+		}
 		delay := r.limiter.When(cn)
 		return ctrl.Result{RequeueAfter: delay}, nil
 	}
 
-	// Prepare context to work in workspace
-	wsCtx := kontext.WithCluster(ctx, logicalcluster.Name(accountWorkspace.Spec.Cluster))
-
 	// Retrieve logical cluster
 	currentWorkspacePath, currentWorkspaceUrl, err := r.retrieveCurrentWorkspacePath(accountWorkspace)
 	if err != nil {
@@ -100,15 +115,19 @@ func (r *AccountInfoSubroutine) Process(ctx context.Context, ro runtimeobject.Ru
 		Type:               instance.Spec.Type,
 		Path:               currentWorkspacePath,
 		URL:                currentWorkspaceUrl,
+		DisplayName:        instance.Spec.DisplayName,
+		Description:        instance.Spec.Description,
+		Creator:            instance.Spec.Creator,
+		Data:               instance.Spec.Data,
 	}
 
-	if instance.Spec.Type == v1alpha1.AccountTypeOrg {
+	if r.accountTypes.IsOrganization(instance.Spec.Type) {
 		accountInfo := &v1alpha1.AccountInfo{ObjectMeta: v1.ObjectMeta{Name: DefaultAccountInfoName}}
-		_, err = controllerutil.CreateOrPatch(wsCtx, r.client, accountInfo, func() error {
+		result, err := controllerutil.CreateOrPatch(wsCtx, r.client, accountInfo, func() error {
 			// the .Spec.FGA.Store.ID is set from an external workspace initializer
 			accountInfo.Spec.Account = selfAccountLocation
 			accountInfo.Spec.ParentAccount = nil
-			accountInfo.Spec.Organization = selfAccountLocation
+			accountInfo.Spec.Organization = publicLocation(selfAccountLocation)
 			accountInfo.Spec.ClusterInfo.CA = r.serverCA
 			return nil
 		})
@@ -116,35 +135,157 @@ func (r *AccountInfoSubroutine) Process(ctx context.Context, ro runtimeobject.Ru
 			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 		}
 
+		err = r.updateStatus(wsCtx, accountInfo, instance, v1alpha1.AccountInfoReasonOrganization, result != controllerutil.OperationResultNone)
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+
 		r.limiter.Forget(cn)
 		return ctrl.Result{}, nil
 	}
 
-	parentAccountInfo, exists, err := r.retrieveAccountInfo(ctx, log)
+	// the parent of a moved account is recorded in its AccountInfo
+	currentAccountInfo, _, err := r.retrieveAccountInfo(wsCtx, log)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	parentAccountInfo, exists, err := r.retrieveAccountInfo(parentContext(ctx, currentAccountInfo), log)
 	if err != nil {
 		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 	}
 
 	if !exists {
+		err = r.markNotSynced(wsCtx, v1.ConditionFalse, v1alpha1.AccountInfoReasonParentPending, "the AccountInfo of the parent account does not exist yet")
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
 		return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("AccountInfo does not yet exist. Retry another time"), true, false)
 	}
 
 	accountInfo := &v1alpha1.AccountInfo{ObjectMeta: v1.ObjectMeta{Name: DefaultAccountInfoName}}
-	_, err = controllerutil.CreateOrUpdate(wsCtx, r.client, accountInfo, func() error {
+	result, err := controllerutil.CreateOrUpdate(wsCtx, r.client, accountInfo, func() error {
+		// a move may have changed the parent since it was resolved
+		if currentAccountInfo != nil && accountInfo.ResourceVersion != currentAccountInfo.ResourceVersion {
+			return kerrors.NewConflict(v1alpha1.GroupVersion.WithResource("accountinfos").GroupResource(), accountInfo.Name,
+				fmt.Errorf("the AccountInfo changed since its parent was resolved"))
+		}
 		accountInfo.Spec.Account = selfAccountLocation
-		accountInfo.Spec.ParentAccount = &parentAccountInfo.Spec.Account
-		accountInfo.Spec.Organization = parentAccountInfo.Spec.Organization
-		accountInfo.Spec.FGA.Store.Id = parentAccountInfo.Spec.FGA.Store.Id
+		parentAccount := publicLocation(parentAccountInfo.Spec.Account)
+		accountInfo.Spec.ParentAccount = &parentAccount
+		accountInfo.Spec.Organization = publicLocation(parentAccountInfo.Spec.Organization)
+		// the store of types that own one is set from an external workspace initializer
+		if !r.accountTypes.OwnsStore(instance.Spec.Type) {
+			accountInfo.Spec.FGA.Store.Id = parentAccountInfo.Spec.FGA.Store.Id
+		}
 		accountInfo.Spec.ClusterInfo.CA = r.serverCA
 		return nil
 	})
 	if err != nil {
 		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 	}
+
+	err = r.updateStatus(wsCtx, accountInfo, instance, v1alpha1.AccountInfoReasonParentResolved, result != controllerutil.OperationResultNone)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
 	r.limiter.Forget(cn)
 	return ctrl.Result{}, nil
 }
 
+// updateStatus records that the AccountInfo was synced from the given generation of the account and whether the account
+// is suspended. The FGA store of an organization is assigned by an external workspace initializer, so StoreAssigned may
+// still be pending after a sync. LastSyncTime only moves if the sync changed the spec, the status is not written if it
+// did not change.
+func (r *AccountInfoSubroutine) updateStatus(ctx context.Context, accountInfo *v1alpha1.AccountInfo, instance *v1alpha1.Account, parentReason string, specChanged bool) error {
+	original := accountInfo.DeepCopy()
+
+	generation := accountInfo.Generation
+	meta.SetStatusCondition(&accountInfo.Status.Conditions, v1.Condition{
+		Type:               v1alpha1.ConditionSynced,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             v1alpha1.AccountInfoReasonSynced,
+		Message:            fmt.Sprintf("synced from generation %d of account %s", instance.Generation, instance.Name),
+	})
+	meta.SetStatusCondition(&accountInfo.Status.Conditions, v1.Condition{
+		Type:               v1alpha1.ConditionParentResolved,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             parentReason,
+	})
+
+	storeCondition := v1.Condition{
+		Type:               v1alpha1.ConditionStoreAssigned,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             v1alpha1.AccountInfoReasonStoreAssigned,
+	}
+	if accountInfo.Spec.FGA.Store.Id == "" {
+		storeCondition.Status = v1.ConditionFalse
+		storeCondition.Reason = v1alpha1.AccountInfoReasonStorePending
+		storeCondition.Message = "the organization has no FGA store assigned yet"
+	}
+	meta.SetStatusCondition(&accountInfo.Status.Conditions, storeCondition)
+
+	suspendedCondition := v1.Condition{
+		Type:               v1alpha1.ConditionSuspended,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             v1alpha1.SuspensionReasonActive,
+	}
+	if instance.Spec.Suspended {
+		suspendedCondition.Status = v1.ConditionTrue
+		suspendedCondition.Reason = v1alpha1.SuspensionReasonSuspended
+		suspendedCondition.Message = fmt.Sprintf("the account %s is suspended", instance.Name)
+	}
+	meta.SetStatusCondition(&accountInfo.Status.Conditions, suspendedCondition)
+
+	accountInfo.Status.ObservedGeneration = instance.Generation
+	if specChanged || accountInfo.Status.LastSyncTime == nil {
+		now := v1.Now()
+		accountInfo.Status.LastSyncTime = &now
+	}
+	accountInfo.Status.OperatorVersion = version.Get()
+
+	if equality.Semantic.DeepEqual(original.Status, accountInfo.Status) {
+		return nil
+	}
+	return r.client.Status().Patch(ctx, accountInfo, client.MergeFrom(original))
+}
+
+// markNotSynced sets the Synced and ParentResolved conditions of an existing AccountInfo while it can not be synced, so
+// that consumers can tell an AccountInfo that is being provisioned from a synced one. A missing AccountInfo is skipped.
+func (r *AccountInfoSubroutine) markNotSynced(ctx context.Context, status v1.ConditionStatus, reason, message string) error {
+	accountInfo := &v1alpha1.AccountInfo{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: DefaultAccountInfoName}, accountInfo); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	original := accountInfo.DeepCopy()
+	changed := false
+	for _, conditionType := range []string{v1alpha1.ConditionSynced, v1alpha1.ConditionParentResolved} {
+		changed = meta.SetStatusCondition(&accountInfo.Status.Conditions, v1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			ObservedGeneration: accountInfo.Generation,
+			Reason:             reason,
+			Message:            message,
+		}) || changed
+	}
+	if !changed {
+		return nil
+	}
+	return r.client.Status().Patch(ctx, accountInfo, client.MergeFrom(original))
+}
+
+// publicLocation strips the fields of a location that are only shared with the workspace of the account itself
+func publicLocation(location v1alpha1.AccountLocation) v1alpha1.AccountLocation {
+	location.Creator = nil
+	location.Data = nil
+	return location
+}
+
 func (r *AccountInfoSubroutine) retrieveAccountInfo(ctx context.Context, log *logger.Logger) (*v1alpha1.AccountInfo, bool, error) {
 	accountInfo := &v1alpha1.AccountInfo{}
 	err := r.client.Get(ctx, client.ObjectKey{Name: "account"}, accountInfo)
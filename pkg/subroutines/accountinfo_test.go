@@ -14,12 +14,15 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
@@ -46,7 +49,7 @@ func (suite *AccountInfoSubroutineTestSuite) SetupTest() {
 	suite.clientMock = new(mocks.Client)
 
 	// Initialize Tested Object(s)
-	suite.testObj = subroutines.NewAccountInfoSubroutine(suite.clientMock, "some-ca")
+	suite.testObj = subroutines.NewAccountInfoSubroutine(suite.clientMock, "some-ca", nil)
 
 	utilruntime.Must(v1alpha1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme.Scheme))
@@ -105,6 +108,21 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForOrganization()
 	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org")
 	suite.mockGetAccountInfoCallNotFound()
 	suite.mockCreateAccountInfoCall(expectedAccountInfo)
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {
+		suite.Equal(int64(3), status.ObservedGeneration)
+		suite.NotNil(status.LastSyncTime)
+		suite.NotEmpty(status.OperatorVersion)
+		suite.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionSynced))
+		parentCondition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionParentResolved)
+		suite.Require().NotNil(parentCondition)
+		suite.Equal(v1alpha1.AccountInfoReasonOrganization, parentCondition.Reason)
+		storeCondition := meta.FindStatusCondition(status.Conditions, v1alpha1.ConditionStoreAssigned)
+		suite.Require().NotNil(storeCondition)
+		suite.Equal(v1.ConditionFalse, storeCondition.Status)
+		suite.Equal(v1alpha1.AccountInfoReasonStorePending, storeCondition.Reason)
+		suite.True(meta.IsStatusConditionFalse(status.Conditions, v1alpha1.ConditionSuspended))
+	})
+	testAccount.Generation = 3
 	ctx := context.Background()
 	ctx = kontext.WithCluster(ctx, "some-cluster-id")
 	// When
@@ -114,6 +132,95 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForOrganization()
 	suite.Nil(err)
 	suite.Assert().Zero(res.RequeueAfter)
 	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Unchanged() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "root-org",
+			Generation:  3,
+			Annotations: map[string]string{"kcp.io/cluster": "asd"},
+		},
+		Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeOrg},
+	}
+	location := v1alpha1.AccountLocation{
+		Name:               "root-org",
+		GeneratedClusterId: "some-cluster-id-root-org",
+		OriginClusterId:    "asd",
+		Path:               "root:openmfp:orgs:root-org",
+		URL:                "https://example.com/root:openmfp:orgs:root-org",
+		Type:               "org",
+	}
+	lastSyncTime := v1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	stored := v1alpha1.AccountInfo{
+		ObjectMeta: v1.ObjectMeta{Name: "account"},
+		Spec: v1alpha1.AccountInfoSpec{
+			ClusterInfo:  v1alpha1.ClusterInfo{CA: "some-ca"},
+			Organization: location,
+			Account:      location,
+		},
+		Status: v1alpha1.AccountInfoStatus{ObservedGeneration: 2, LastSyncTime: &lastSyncTime},
+	}
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org")
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			*obj.(*v1alpha1.AccountInfo) = *stored.DeepCopy()
+		}).
+		Return(nil)
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {
+		suite.Equal(int64(3), status.ObservedGeneration)
+		suite.Equal(&lastSyncTime, status.LastSyncTime)
+		stored.Status = status
+	})
+	ctx := kontext.WithCluster(context.Background(), "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+	suite.Require().Nil(err)
+	_, err = suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.clientMock.AssertNumberOfCalls(suite.T(), "Status", 1)
+	suite.clientMock.AssertNotCalled(suite.T(), "Patch", mock.Anything, mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Status_Update_Failed() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "root-org",
+			Annotations: map[string]string{
+				"kcp.io/cluster": "asd",
+			},
+		},
+		Spec: v1alpha1.AccountSpec{
+			Type: v1alpha1.AccountTypeOrg,
+		},
+	}
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org")
+	suite.mockGetAccountInfoCallNotFound()
+	suite.clientMock.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+	statusMock := &mocks.SubResourceClient{}
+	statusMock.EXPECT().Patch(mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failed"))
+	suite.clientMock.EXPECT().Status().Return(statusMock)
+	ctx := kontext.WithCluster(context.Background(), "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.NotNil(err)
+	suite.Equal("failed", err.Err().Error())
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
 }
 
 func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Missing_Context() {
@@ -180,6 +287,30 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Work
 	ctx = kontext.WithCluster(ctx, "some-cluster-id")
 
 	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseInitializing, "root:openmfp:orgs")
+	suite.mockGetAccountInfoCallNotFound()
+
+	// When
+	res, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Assert().NotZero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_Workspace_Not_Ready_Marks_AccountInfo() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{Name: "example-account"},
+		Spec:       v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount},
+	}
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseUnavailable, "root:openmfp:orgs:root-org:example-account")
+	suite.mockGetSyncedAccountInfo()
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {
+		suite.verifyNotSynced(status, v1.ConditionUnknown, v1alpha1.AccountInfoReasonWorkspaceNotReady)
+	})
 
 	// When
 	res, err := suite.testObj.Process(ctx, testAccount)
@@ -188,6 +319,34 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Work
 	suite.Nil(err)
 	suite.Assert().NotZero(res.RequeueAfter)
 	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_Workspace_Provisioning_Timeout() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{Name: "example-account"},
+		Spec:       v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount},
+	}
+	cfg := config.OperatorConfig{}
+	cfg.Subroutines.Workspace.ProvisioningTimeout = 10 * time.Minute
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+	ctx = kontext.WithCluster(ctx, "some-cluster-id")
+
+	mockGetWorkspaceCreatedBefore(suite.clientMock, time.Hour)
+	suite.mockGetSyncedAccountInfo()
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {})
+
+	// When
+	res, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.Equal("the account workspace did not become ready within 10m0s", err.Err().Error())
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
 }
 
 func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForOrganization_Workspace_Not_Ready_no_Context() {
@@ -321,7 +480,12 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForAccount() {
 			},
 		},
 		Spec: v1alpha1.AccountSpec{
-			Type: v1alpha1.AccountTypeAccount,
+			Type:        v1alpha1.AccountTypeAccount,
+			DisplayName: "Example Account",
+			Suspended:   true,
+			Description: ptr.To("An example account"),
+			Creator:     ptr.To("creator"),
+			Data:        &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)},
 		},
 	}
 	expectedAccountInfo := v1alpha1.AccountInfo{
@@ -344,6 +508,10 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForAccount() {
 				Path:               "root:openmfp:orgs:root-org:example-account",
 				Type:               "account",
 				URL:                "https://example.com/root:openmfp:orgs:root-org:example-account",
+				DisplayName:        "Example Account",
+				Description:        ptr.To("An example account"),
+				Creator:            ptr.To("creator"),
+				Data:               &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)},
 			},
 			ParentAccount: &v1alpha1.AccountLocation{
 				Name:               "root-org",
@@ -361,15 +529,26 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForAccount() {
 	}
 
 	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org:example-account")
+	// the creator and data of the parent are not shared with child accounts
+	parentAccount := expectedAccountInfo.Spec.Organization
+	parentAccount.Creator = ptr.To("org-creator")
+	parentAccount.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-0"}`)}
 	parentAccountInfoSpec := v1alpha1.AccountInfoSpec{
 		Organization:  expectedAccountInfo.Spec.Organization,
 		ParentAccount: nil,
-		Account:       expectedAccountInfo.Spec.Organization,
+		Account:       parentAccount,
 		FGA:           v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "1"}},
 	}
+	suite.mockGetAccountInfoCallNotFound().Once()
 	suite.mockGetAccountInfo(parentAccountInfoSpec).Once()
 	suite.mockGetAccountInfoCallNotFound()
 	suite.mockCreateAccountInfoCall(expectedAccountInfo)
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {
+		suite.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionSynced))
+		suite.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionParentResolved))
+		suite.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionStoreAssigned))
+		suite.True(meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionSuspended))
+	})
 	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
 
 	// When
@@ -378,6 +557,80 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_OK_ForAccount() {
 	// Then
 	suite.Nil(err)
 	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForMovedAccount() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "example-account",
+			Annotations: map[string]string{"kcp.io/cluster": "asd"},
+		},
+		Spec: v1alpha1.AccountSpec{
+			Type: v1alpha1.AccountTypeAccount,
+		},
+	}
+	org := v1alpha1.AccountLocation{Name: "org", GeneratedClusterId: "org-cluster", Type: v1alpha1.AccountTypeOrg}
+	newParent := v1alpha1.AccountLocation{Name: "new-parent", GeneratedClusterId: "new-parent-cluster", Type: "account"}
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org:example-account")
+	// the AccountInfo of the account records the parent it was moved to
+	mockGetAccountInfos(suite.clientMock, map[string]v1alpha1.AccountInfoSpec{
+		"some-cluster-id-example-account": {ParentAccount: &newParent, Organization: org},
+		"new-parent-cluster":              {Account: newParent, Organization: org},
+	})
+	suite.clientMock.EXPECT().Update(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) {
+			suite.Equal(&newParent, obj.(*v1alpha1.AccountInfo).Spec.ParentAccount)
+		}).
+		Return(nil)
+	suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {})
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForMovedAccount_Concurrent_Move() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "example-account",
+			Annotations: map[string]string{"kcp.io/cluster": "asd"},
+		},
+		Spec: v1alpha1.AccountSpec{
+			Type: v1alpha1.AccountTypeAccount,
+		},
+	}
+	parent := v1alpha1.AccountLocation{Name: "parent", GeneratedClusterId: "some-cluster-id", Type: "account"}
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org:example-account")
+	resourceVersion := 0
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			resourceVersion++
+			actual := obj.(*v1alpha1.AccountInfo)
+			actual.Name = key.Name
+			actual.ResourceVersion = fmt.Sprint(resourceVersion)
+			actual.Spec = v1alpha1.AccountInfoSpec{Account: parent, ParentAccount: &parent}
+		}).
+		Return(nil)
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(kerrors.IsConflict(err.Err()))
+	suite.True(err.Retry())
+	suite.clientMock.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
 }
 
 func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForAccount_No_Parent() {
@@ -410,6 +663,43 @@ func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForAccount_No_Parent
 	suite.clientMock.AssertExpectations(suite.T())
 }
 
+func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForAccount_No_Parent_Marks_AccountInfo() {
+	// Given
+	testAccount := &v1alpha1.Account{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "example-account",
+			Annotations: map[string]string{"kcp.io/cluster": "asd"},
+		},
+		Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount},
+	}
+
+	suite.mockGetWorkspaceByName(kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org:example-account")
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+			if cluster, _ := kontext.ClusterFrom(ctx); cluster.String() != "some-cluster-id-example-account" {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			obj.(*v1alpha1.AccountInfo).Status.Conditions = []v1.Condition{
+				{Type: v1alpha1.ConditionSynced, Status: v1.ConditionTrue, Reason: v1alpha1.AccountInfoReasonSynced},
+			}
+			return nil
+		})
+	statusMock := suite.mockPatchAccountInfoStatus(func(status v1alpha1.AccountInfoStatus) {
+		suite.verifyNotSynced(status, v1.ConditionFalse, v1alpha1.AccountInfoReasonParentPending)
+	})
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+	statusMock.AssertExpectations(suite.T())
+}
+
 func (suite *AccountInfoSubroutineTestSuite) TestProcessing_ForAccount_Parent_Lookup_Failed() {
 	// Given
 	testAccount := &v1alpha1.Account{
@@ -485,6 +775,29 @@ func (suite *AccountInfoSubroutineTestSuite) TestFinalizeNoContext() {
 	})
 }
 
+func (suite *AccountInfoSubroutineTestSuite) verifyNotSynced(status v1alpha1.AccountInfoStatus, conditionStatus v1.ConditionStatus, reason string) {
+	for _, conditionType := range []string{v1alpha1.ConditionSynced, v1alpha1.ConditionParentResolved} {
+		condition := meta.FindStatusCondition(status.Conditions, conditionType)
+		suite.Require().NotNil(condition)
+		suite.Equal(conditionStatus, condition.Status)
+		suite.Equal(reason, condition.Reason)
+	}
+}
+
+func (suite *AccountInfoSubroutineTestSuite) mockGetSyncedAccountInfo() *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual := obj.(*v1alpha1.AccountInfo)
+			actual.Name = key.Name
+			actual.Status.Conditions = []v1.Condition{
+				{Type: v1alpha1.ConditionSynced, Status: v1.ConditionTrue, Reason: v1alpha1.AccountInfoReasonSynced},
+				{Type: v1alpha1.ConditionParentResolved, Status: v1.ConditionTrue, Reason: v1alpha1.AccountInfoReasonParentResolved},
+			}
+		}).
+		Return(nil)
+}
+
 func (suite *AccountInfoSubroutineTestSuite) mockGetAccountInfoCallNotFound() *mocks.Client_Get_Call {
 	return suite.clientMock.EXPECT().
 		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
@@ -510,6 +823,18 @@ func (suite *AccountInfoSubroutineTestSuite) mockCreateAccountInfoCall(info v1al
 		Return(nil)
 }
 
+func (suite *AccountInfoSubroutineTestSuite) mockPatchAccountInfoStatus(check func(status v1alpha1.AccountInfoStatus)) *mocks.SubResourceClient {
+	statusMock := &mocks.SubResourceClient{}
+	statusMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo"), mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) {
+			check(obj.(*v1alpha1.AccountInfo).Status)
+		}).
+		Return(nil)
+	suite.clientMock.EXPECT().Status().Return(statusMock)
+	return statusMock
+}
+
 func (suite *AccountInfoSubroutineTestSuite) mockGetWorkspaceByName(ready kcpcorev1alpha1.LogicalClusterPhaseType, path string) *mocks.Client_Get_Call {
 	return suite.clientMock.EXPECT().
 		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
@@ -0,0 +1,300 @@
+package subroutines
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/fga/helpers"
+	"github.com/platform-mesh/golang-commons/logger"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+var _ subroutine.Subroutine = (*AccountMoveSubroutine)(nil)
+
+const (
+	AccountMoveSubroutineName = "AccountMoveSubroutine"
+
+	// maxAncestors bounds the walk up the account hierarchy, in case the parent references form a cycle
+	maxAncestors = 64
+)
+
+// AccountMoveSubroutine links an account to a new parent account. It rewrites the parent tuple in FGA and the parent
+// in the AccountInfo of the account. The AccountInfos below the moved account reference the moved account and the
+// organization, neither changes with a move within an organization.
+type AccountMoveSubroutine struct {
+	client         client.Client
+	authorizer     client.Client
+	fgaClient      openfgav1.OpenFGAServiceClient
+	objectType     string
+	parentRelation string
+	limits         v1alpha1.DepthLimits
+	accountTypes   *v1alpha1.AccountTypeRegistry
+}
+
+// NewAccountMoveSubroutine creates the subroutine, fgaClient may be nil if FGA is disabled.
+func NewAccountMoveSubroutine(cl, authorizer client.Client, fgaClient openfgav1.OpenFGAServiceClient, objectType, parentRelation string, limits v1alpha1.DepthLimits, accountTypes *v1alpha1.AccountTypeRegistry) *AccountMoveSubroutine {
+	return &AccountMoveSubroutine{client: cl, authorizer: authorizer, fgaClient: fgaClient, objectType: objectType, parentRelation: parentRelation, limits: limits, accountTypes: accountTypes}
+}
+
+func (r *AccountMoveSubroutine) GetName() string { return AccountMoveSubroutineName }
+
+func (r *AccountMoveSubroutine) Finalizers() []string { return nil }
+
+func (r *AccountMoveSubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (r *AccountMoveSubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	move := ro.(*v1alpha1.AccountMove)
+	log := logger.LoadLoggerFromContext(ctx)
+
+	// a move is executed once, its spec can not change afterwards
+	if move.Status.Phase == v1alpha1.AccountMovePhaseSucceeded || move.Status.Phase == v1alpha1.AccountMovePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	move.Status.Phase = v1alpha1.AccountMovePhasePending
+
+	account := &v1alpha1.Account{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: move.Spec.Account}, account); err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(move, fmt.Errorf("account %q does not exist", move.Spec.Account))
+		}
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	// organizations have no parent, the parent of an account with its own store is not part of that store
+	if r.accountTypes.IsOrganization(account.Spec.Type) {
+		return r.fail(move, fmt.Errorf("accounts of the type %s are organizations and can not be moved", account.Spec.Type))
+	}
+	if r.accountTypes.OwnsStore(account.Spec.Type) {
+		return r.fail(move, fmt.Errorf("accounts of the type %s have their own FGA store and can not be moved", account.Spec.Type))
+	}
+
+	accountWorkspace, err := retrieveWorkspace(ctx, account, r.client, log)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	wsCtx := kontext.WithCluster(ctx, logicalcluster.Name(accountWorkspace.Spec.Cluster))
+
+	accountInfo, err := r.getAccountInfo(wsCtx)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	if accountInfo.Spec.ParentAccount == nil {
+		return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("the AccountInfo of account %q has no parent yet", account.Name), true, false)
+	}
+
+	targetCtx := kontext.WithCluster(ctx, logicalcluster.Name(move.Spec.TargetParentClusterID))
+	targetInfo, err := r.getAccountInfo(targetCtx)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return r.fail(move, fmt.Errorf("no account workspace with the cluster id %q exists", move.Spec.TargetParentClusterID))
+		}
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	if targetInfo.Spec.Organization.GeneratedClusterId != accountInfo.Spec.Organization.GeneratedClusterId {
+		return r.fail(move, fmt.Errorf("account %q can not be moved to the organization %q, moves across organizations are not allowed",
+			account.Name, targetInfo.Spec.Organization.Name))
+	}
+	if err := r.accountTypes.ValidatePlacement(account.Spec.Type, targetInfo); err != nil {
+		return r.fail(move, err)
+	}
+	inSubtree, err := r.isInSubtree(ctx, targetInfo, accountWorkspace.Spec.Cluster)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	if inSubtree {
+		return r.fail(move, fmt.Errorf("account %q can not be moved below itself", account.Name))
+	}
+
+	if move.Spec.Creator == nil {
+		return r.fail(move, fmt.Errorf("the creator of the move is unknown, it is recorded by the AccountMove webhook"))
+	}
+	allowed, err := r.creatorAllowed(targetCtx, move.Spec.Creator)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	if !allowed {
+		return r.fail(move, fmt.Errorf("%q is not allowed to create accounts below the account %q", move.Spec.Creator.Username, targetInfo.Spec.Account.Name))
+	}
+
+	if r.limits.For(accountInfo.Spec.Organization.Name) > 0 {
+		height, err := r.subtreeHeight(ctx, accountInfo)
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+		err = v1alpha1.ValidateSubtreeDepth(ctx, r.client, r.limits, targetInfo, height)
+		var depthErr *v1alpha1.DepthExceededError
+		if errors.As(err, &depthErr) {
+			return r.fail(move, fmt.Errorf("account %q can not be moved below the account %q: %w", account.Name, targetInfo.Spec.Account.Name, err))
+		}
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+	}
+
+	if move.Status.PreviousParent == nil {
+		previousParent := *accountInfo.Spec.ParentAccount
+		move.Status.PreviousParent = &previousParent
+	}
+
+	// the AccountInfoSubroutine resolves the parent recorded here from now on
+	original := accountInfo.DeepCopy()
+	parent := publicLocation(targetInfo.Spec.Account)
+	accountInfo.Spec.ParentAccount = &parent
+	if err := r.client.Patch(wsCtx, accountInfo, client.MergeFrom(original)); err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	if err := r.rewriteParentTuple(ctx, accountInfo, move.Status.PreviousParent, &parent); err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	log.Info().Str("account", account.Name).Str("parent", parent.Path).Msg("moved account")
+	now := v1.Now()
+	move.Status.Phase = v1alpha1.AccountMovePhaseSucceeded
+	move.Status.CompletionTime = &now
+	return ctrl.Result{}, nil
+}
+
+// isInSubtree walks from the given AccountInfo up to the organization and reports whether one of the accounts on the
+// way has the given workspace cluster. The walk follows the parent references, which differ from the workspace path
+// for accounts that were moved before.
+func (r *AccountMoveSubroutine) isInSubtree(ctx context.Context, info *v1alpha1.AccountInfo, cluster string) (bool, error) {
+	for range maxAncestors {
+		if info.Spec.Account.GeneratedClusterId == cluster {
+			return true, nil
+		}
+		if info.Spec.ParentAccount == nil {
+			return false, nil
+		}
+
+		var err error
+		info, err = r.getAccountInfo(kontext.WithCluster(ctx, logicalcluster.Name(info.Spec.ParentAccount.GeneratedClusterId)))
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, fmt.Errorf("the account hierarchy is deeper than %d levels", maxAncestors)
+}
+
+// creatorAllowed checks with a SubjectAccessReview whether the creator of a move may create accounts in the workspace of
+// the new parent in the context.
+func (r *AccountMoveSubroutine) creatorAllowed(ctx context.Context, creator *authenticationv1.UserInfo) (bool, error) {
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Verb:     "create",
+			Group:    v1alpha1.GroupVersion.Group,
+			Resource: "accounts",
+		},
+		User:   creator.Username,
+		UID:    creator.UID,
+		Groups: creator.Groups,
+	}}
+	if len(creator.Extra) > 0 {
+		review.Spec.Extra = make(map[string]authorizationv1.ExtraValue, len(creator.Extra))
+		for key, value := range creator.Extra {
+			review.Spec.Extra[key] = authorizationv1.ExtraValue(value)
+		}
+	}
+	if err := r.authorizer.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// subtreeHeight returns how many levels of accounts are nested below the account of the AccountInfo. The AccountInfos
+// of all workspaces are read, the accounts that were moved below the account are part of its subtree as well.
+func (r *AccountMoveSubroutine) subtreeHeight(ctx context.Context, accountInfo *v1alpha1.AccountInfo) (int, error) {
+	infos := &v1alpha1.AccountInfoList{}
+	if err := r.client.List(kontext.WithCluster(ctx, ""), infos); err != nil {
+		return 0, err
+	}
+	children := map[string][]string{}
+	for _, info := range infos.Items {
+		if info.Spec.ParentAccount == nil || info.Spec.Organization.GeneratedClusterId != accountInfo.Spec.Organization.GeneratedClusterId {
+			continue
+		}
+		parent := info.Spec.ParentAccount.GeneratedClusterId
+		children[parent] = append(children[parent], info.Spec.Account.GeneratedClusterId)
+	}
+
+	level := children[accountInfo.Spec.Account.GeneratedClusterId]
+	for height := 0; height < maxAncestors; height++ {
+		if len(level) == 0 {
+			return height, nil
+		}
+		var next []string
+		for _, cluster := range level {
+			next = append(next, children[cluster]...)
+		}
+		level = next
+	}
+	return 0, fmt.Errorf("the account hierarchy is deeper than %d levels", maxAncestors)
+}
+
+// rewriteParentTuple replaces the parent relation that the FGASubroutine wrote for the previous parent
+func (r *AccountMoveSubroutine) rewriteParentTuple(ctx context.Context, accountInfo *v1alpha1.AccountInfo, previousParent, parent *v1alpha1.AccountLocation) error {
+	if r.fgaClient == nil {
+		return nil
+	}
+	if accountInfo.Spec.FGA.Store.Id == "" {
+		return fmt.Errorf("FGA Store Id is empty")
+	}
+
+	object := fmt.Sprintf("%s:%s/%s", r.objectType, accountInfo.Spec.Account.OriginClusterId, accountInfo.Spec.Account.Name)
+	requests := []*openfgav1.WriteRequest{{
+		StoreId: accountInfo.Spec.FGA.Store.Id,
+		Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{{
+			Object:   object,
+			Relation: r.parentRelation,
+			User:     fmt.Sprintf("%s:%s/%s", r.objectType, parent.OriginClusterId, parent.Name),
+		}}},
+	}}
+	if previousParent.GeneratedClusterId != parent.GeneratedClusterId {
+		requests = append(requests, &openfgav1.WriteRequest{
+			StoreId: accountInfo.Spec.FGA.Store.Id,
+			Deletes: &openfgav1.WriteRequestDeletes{TupleKeys: []*openfgav1.TupleKeyWithoutCondition{{
+				Object:   object,
+				Relation: r.parentRelation,
+				User:     fmt.Sprintf("%s:%s/%s", r.objectType, previousParent.OriginClusterId, previousParent.Name),
+			}}},
+		})
+	}
+
+	for _, request := range requests {
+		_, err := r.fgaClient.Write(ctx, request)
+		// written before or deleted before, if the move is repeated after an error
+		if err != nil && !helpers.IsDuplicateWriteError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *AccountMoveSubroutine) fail(move *v1alpha1.AccountMove, err error) (ctrl.Result, errors.OperatorError) {
+	move.Status.Phase = v1alpha1.AccountMovePhaseFailed
+	move.Status.Message = err.Error()
+	return ctrl.Result{}, errors.NewOperatorError(err, false, false)
+}
+
+func (r *AccountMoveSubroutine) getAccountInfo(ctx context.Context) (*v1alpha1.AccountInfo, error) {
+	accountInfo := &v1alpha1.AccountInfo{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: DefaultAccountInfoName}, accountInfo); err != nil {
+		return nil, err
+	}
+	return accountInfo, nil
+}
@@ -0,0 +1,350 @@
+package subroutines_test
+
+import (
+	"context"
+	"testing"
+
+	kcpcorev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
+)
+
+var (
+	moveTestOrg      = v1alpha1.AccountLocation{Name: "org", GeneratedClusterId: "org-ws", OriginClusterId: "orgs", Path: "root:orgs:org", Type: v1alpha1.AccountTypeOrg}
+	moveTestOtherOrg = v1alpha1.AccountLocation{Name: "other", GeneratedClusterId: "other-ws", OriginClusterId: "orgs", Path: "root:orgs:other", Type: v1alpha1.AccountTypeOrg}
+	moveTestTarget   = v1alpha1.AccountLocation{Name: "a", GeneratedClusterId: "a-ws", OriginClusterId: "org-ws", Path: "root:orgs:org:a", Type: v1alpha1.AccountTypeAccount, Creator: ptr.To("creator")}
+	moveTestTeam     = v1alpha1.AccountLocation{Name: "team", GeneratedClusterId: "some-cluster-id-team", OriginClusterId: "org-ws", Path: "root:orgs:org:team", Type: v1alpha1.AccountTypeAccount}
+	moveTestChild    = v1alpha1.AccountLocation{Name: "child", GeneratedClusterId: "child-ws", OriginClusterId: "some-cluster-id-team", Path: "root:orgs:org:team:child", Type: v1alpha1.AccountTypeAccount}
+)
+
+type AccountMoveSubroutineTestSuite struct {
+	suite.Suite
+
+	// Tested Object(s)
+	testObj *subroutines.AccountMoveSubroutine
+
+	// Mocks
+	clientMock     *mocks.Client
+	authorizerMock *mocks.Client
+	fgaMock        *mocks.OpenFGAServiceClient
+
+	context context.Context
+	log     *logger.Logger
+}
+
+func (suite *AccountMoveSubroutineTestSuite) SetupTest() {
+	// Setup Mocks
+	suite.clientMock = new(mocks.Client)
+	suite.authorizerMock = new(mocks.Client)
+	suite.fgaMock = new(mocks.OpenFGAServiceClient)
+
+	// Initialize Tested Object(s)
+	suite.testObj = subroutines.NewAccountMoveSubroutine(suite.clientMock, suite.authorizerMock, suite.fgaMock, "account", "parent", v1alpha1.DepthLimits{}, nil)
+
+	var err error
+	suite.log, err = logger.New(logger.DefaultConfig())
+	suite.Require().NoError(err)
+	suite.context = logger.SetLoggerInContext(kontext.WithCluster(context.Background(), "org-ws"), suite.log)
+}
+
+func TestAccountMoveSubroutineTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountMoveSubroutineTestSuite))
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestGetName_OK() {
+	suite.Equal(subroutines.AccountMoveSubroutineName, suite.testObj.GetName())
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Moves_Account() {
+	// Given
+	move := newAccountMove("a-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	suite.mockReview("a-ws", true)
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo"), mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			suite.Equal("some-cluster-id-team", cluster.String())
+			parent := obj.(*v1alpha1.AccountInfo).Spec.ParentAccount
+			suite.Equal("a", parent.Name)
+			suite.Nil(parent.Creator)
+		}).
+		Return(nil)
+	suite.fgaMock.EXPECT().
+		Write(mock.Anything, &openfgav1.WriteRequest{
+			StoreId: "store",
+			Writes: &openfgav1.WriteRequestWrites{TupleKeys: []*openfgav1.TupleKey{
+				{Object: "account:org-ws/team", Relation: "parent", User: "account:org-ws/a"},
+			}},
+		}).
+		Return(&openfgav1.WriteResponse{}, nil).Once()
+	suite.fgaMock.EXPECT().
+		Write(mock.Anything, &openfgav1.WriteRequest{
+			StoreId: "store",
+			Deletes: &openfgav1.WriteRequestDeletes{TupleKeys: []*openfgav1.TupleKeyWithoutCondition{
+				{Object: "account:org-ws/team", Relation: "parent", User: "account:orgs/org"},
+			}},
+		}).
+		Return(nil, newFgaError(openfgav1.ErrorCode_write_failed_due_to_invalid_input, "tuple does not exist")).Once()
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.Nil(err)
+	suite.verifySucceeded(move)
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Moves_Account_Back() {
+	// Given
+	move := newAccountMove("org-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	suite.mockReview("org-ws", true)
+	suite.clientMock.EXPECT().Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo"), mock.Anything).Return(nil)
+	suite.fgaMock.EXPECT().Write(mock.Anything, mock.Anything).Return(&openfgav1.WriteResponse{}, nil)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.Nil(err)
+	suite.verifySucceeded(move)
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Across_Organizations() {
+	// Given
+	move := newAccountMove("other-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "account \"team\" can not be moved to the organization \"other\", moves across organizations are not allowed")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Below_Itself() {
+	// Given
+	move := newAccountMove("child-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "account \"team\" can not be moved below itself")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Creator_Not_Allowed() {
+	// Given
+	move := newAccountMove("a-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	suite.mockReview("a-ws", false)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "\"user\" is not allowed to create accounts below the account \"a\"")
+	suite.authorizerMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Without_Creator() {
+	// Given
+	move := newAccountMove("a-ws")
+	move.Spec.Creator = nil
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "the creator of the move is unknown, it is recorded by the AccountMove webhook")
+	suite.authorizerMock.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Exceeds_Depth() {
+	// Given
+	testObj := subroutines.NewAccountMoveSubroutine(suite.clientMock, suite.authorizerMock, suite.fgaMock, "account", "parent", v1alpha1.DepthLimits{Default: 2}, nil)
+	move := newAccountMove("a-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	suite.mockReview("a-ws", true)
+	// the child of the moved account would be nested three levels deep
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfoList")).
+		Run(func(ctx context.Context, list client.ObjectList, _ ...client.ListOption) {
+			for _, spec := range moveTestAccountInfoSpecs() {
+				list.(*v1alpha1.AccountInfoList).Items = append(list.(*v1alpha1.AccountInfoList).Items, v1alpha1.AccountInfo{Spec: spec})
+			}
+		}).
+		Return(nil)
+
+	// When
+	_, err := testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "account \"team\" can not be moved below the account \"a\": accounts in the organization \"org\" can be nested at most 2 levels deep")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Unknown_Target() {
+	// Given
+	move := newAccountMove("unknown-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeAccount))
+	suite.mockGetAccountInfos()
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "no account workspace with the cluster id \"unknown-ws\" exists")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Organization() {
+	// Given
+	move := newAccountMove("a-ws")
+	suite.mockGetMovedAccount(newMovedAccount(v1alpha1.AccountTypeOrg))
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "accounts of the type org are organizations and can not be moved")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Missing_Account() {
+	// Given
+	move := newAccountMove("a-ws")
+	suite.mockGetMovedAccount(nil)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.verifyFailed(move, err, "account \"team\" does not exist")
+}
+
+func (suite *AccountMoveSubroutineTestSuite) TestProcessing_Completed_Move() {
+	// Given
+	move := newAccountMove("a-ws")
+	move.Status.Phase = v1alpha1.AccountMovePhaseSucceeded
+
+	// When
+	_, err := suite.testObj.Process(suite.context, move)
+
+	// Then
+	suite.Nil(err)
+	suite.Equal(v1alpha1.AccountMovePhaseSucceeded, move.Status.Phase)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountMoveSubroutineTestSuite) verifySucceeded(move *v1alpha1.AccountMove) {
+	suite.Equal(v1alpha1.AccountMovePhaseSucceeded, move.Status.Phase)
+	suite.Require().NotNil(move.Status.PreviousParent)
+	suite.Equal("org", move.Status.PreviousParent.Name)
+	suite.NotNil(move.Status.CompletionTime)
+	suite.clientMock.AssertExpectations(suite.T())
+	suite.fgaMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountMoveSubroutineTestSuite) verifyFailed(move *v1alpha1.AccountMove, err interface {
+	Err() error
+	Retry() bool
+}, message string) {
+	suite.Equal(v1alpha1.AccountMovePhaseFailed, move.Status.Phase)
+	suite.Require().NotNil(err)
+	suite.Equal(message, err.Err().Error())
+	suite.Equal(message, move.Status.Message)
+	suite.False(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+	suite.fgaMock.AssertExpectations(suite.T())
+}
+
+func (suite *AccountMoveSubroutineTestSuite) mockGetMovedAccount(account *v1alpha1.Account) *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Account")).
+		RunAndReturn(func(_ context.Context, key types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			if account == nil {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			*obj.(*v1alpha1.Account) = *account.DeepCopy()
+			return nil
+		})
+}
+
+// mockReview answers the SubjectAccessReview for the creator of the move in the workspace of the new parent
+func (suite *AccountMoveSubroutineTestSuite) mockReview(cluster string, allowed bool) *mocks.Client_Create_Call {
+	return suite.authorizerMock.EXPECT().
+		Create(mock.Anything, mock.AnythingOfType("*v1.SubjectAccessReview")).
+		Run(func(ctx context.Context, obj client.Object, _ ...client.CreateOption) {
+			actual, _ := kontext.ClusterFrom(ctx)
+			suite.Equal(cluster, actual.String())
+			review := obj.(*authorizationv1.SubjectAccessReview)
+			suite.Equal("user", review.Spec.User)
+			suite.Equal([]string{"team"}, review.Spec.Groups)
+			suite.Equal(&authorizationv1.ResourceAttributes{Verb: "create", Group: "core.openmfp.org", Resource: "accounts"}, review.Spec.ResourceAttributes)
+			review.Status.Allowed = allowed
+		}).
+		Return(nil)
+}
+
+func (suite *AccountMoveSubroutineTestSuite) mockGetAccountInfos() *mocks.Client_Get_Call {
+	return mockGetAccountInfos(suite.clientMock, moveTestAccountInfoSpecs())
+}
+
+func moveTestAccountInfoSpecs() map[string]v1alpha1.AccountInfoSpec {
+	return map[string]v1alpha1.AccountInfoSpec{
+		"org-ws":               {Account: moveTestOrg, Organization: moveTestOrg, FGA: v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "store"}}},
+		"other-ws":             {Account: moveTestOtherOrg, Organization: moveTestOtherOrg},
+		"a-ws":                 {Account: moveTestTarget, ParentAccount: &moveTestOrg, Organization: moveTestOrg},
+		"some-cluster-id-team": {Account: moveTestTeam, ParentAccount: &moveTestOrg, Organization: moveTestOrg, FGA: v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "store"}}},
+		"child-ws":             {Account: moveTestChild, ParentAccount: &moveTestTeam, Organization: moveTestOrg},
+	}
+}
+
+func newAccountMove(targetParentClusterID string) *v1alpha1.AccountMove {
+	return &v1alpha1.AccountMove{
+		ObjectMeta: metav1.ObjectMeta{Name: "move-team"},
+		Spec: v1alpha1.AccountMoveSpec{
+			Account:               "team",
+			TargetParentClusterID: targetParentClusterID,
+			Creator:               &authenticationv1.UserInfo{Username: "user", Groups: []string{"team"}},
+		},
+	}
+}
+
+func newMovedAccount(accountType v1alpha1.AccountType) *v1alpha1.Account {
+	return &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec:       v1alpha1.AccountSpec{Type: accountType},
+	}
+}
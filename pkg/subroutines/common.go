@@ -2,13 +2,21 @@ package subroutines
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	kcpcorev1alpha "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	commonconfig "github.com/platform-mesh/golang-commons/config"
 	"github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
 	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
 )
 
 func retrieveWorkspace(ctx context.Context, instance *v1alpha1.Account, c client.Client, log *logger.Logger) (*kcptenancyv1alpha.Workspace, error) {
@@ -21,3 +29,59 @@ func retrieveWorkspace(ctx context.Context, instance *v1alpha1.Account, c client
 	}
 	return ws, nil
 }
+
+// parentContext returns the context of the workspace of the parent account. That is the workspace that holds the
+// account, unless the AccountInfo of the account records a different parent because the account was moved. Only the
+// operator writes AccountInfos, accountInfo is nil before it exists.
+func parentContext(ctx context.Context, accountInfo *v1alpha1.AccountInfo) context.Context {
+	if accountInfo == nil || accountInfo.Spec.ParentAccount == nil || accountInfo.Spec.ParentAccount.GeneratedClusterId == "" {
+		return ctx
+	}
+	return kontext.WithCluster(ctx, logicalcluster.Name(accountInfo.Spec.ParentAccount.GeneratedClusterId))
+}
+
+// retrieveAccountInfoOf returns the AccountInfo in the workspace of the account, nil as long as the workspace is not
+// ready or the AccountInfo does not exist
+func retrieveAccountInfoOf(ctx context.Context, c client.Client, instance *v1alpha1.Account) (*v1alpha1.AccountInfo, error) {
+	ws := &kcptenancyv1alpha.Workspace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: instance.Name}, ws); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if ws.Status.Phase != kcpcorev1alpha.LogicalClusterPhaseReady {
+		return nil, nil
+	}
+
+	accountInfo := &v1alpha1.AccountInfo{}
+	err := c.Get(kontext.WithCluster(ctx, logicalcluster.Name(ws.Spec.Cluster)), client.ObjectKey{Name: DefaultAccountInfoName}, accountInfo)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return accountInfo, nil
+}
+
+// workspaceTimeoutError returns an error if the workspace did not become ready within the provisioning timeout. Waiting
+// for it is not retried any longer, the account is reconciled again once the workspace changes.
+func workspaceTimeoutError(ctx context.Context, ws *kcptenancyv1alpha.Workspace) error {
+	cfg, _ := commonconfig.LoadConfigFromContext(ctx).(config.OperatorConfig)
+	timeout := cfg.Subroutines.Workspace.ProvisioningTimeout
+	if !provisioningTimedOut(ws, timeout, time.Now()) {
+		return nil
+	}
+	return fmt.Errorf("the account workspace did not become ready within %s", timeout)
+}
+
+// releaseFinalizers removes finalizers of subroutines that are disabled from the account. Accounts created while they
+// were enabled still carry them and nothing else would remove them.
+func releaseFinalizers(ctx context.Context, c client.Client, instance *v1alpha1.Account, finalizers ...string) error {
+	original := instance.DeepCopy()
+	released := false
+	for _, finalizer := range finalizers {
+		if controllerutil.RemoveFinalizer(instance, finalizer) {
+			released = true
+		}
+	}
+	if !released {
+		return nil
+	}
+	return c.Patch(ctx, instance, client.MergeFrom(original))
+}
@@ -2,13 +2,19 @@ package subroutines_test
 
 import (
 	"context"
+	"time"
 
 	kcpcorev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
 	"github.com/stretchr/testify/mock"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
+	"github.com/openmfp/account-operator/api/v1alpha1"
 	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
 )
 
@@ -30,3 +36,38 @@ func mockGetWorkspaceByName(clientMock *mocks.Client, ready kcpcorev1alpha1.Logi
 		}).
 		Return(nil)
 }
+
+// mockGetWorkspaceCreatedBefore returns a workspace that is still initializing after the given time
+func mockGetWorkspaceCreatedBefore(clientMock *mocks.Client, age time.Duration) *mocks.Client_Get_Call {
+	return clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual, _ := obj.(*kcptenancyv1alpha.Workspace)
+			actual.Name = key.Name
+			actual.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+			actual.Spec.Cluster = "some-cluster-id-" + key.Name
+			actual.Status.Phase = kcpcorev1alpha1.LogicalClusterPhaseInitializing
+		}).
+		Return(nil)
+}
+
+func mockGetWorkspaceNotFound(clientMock *mocks.Client) *mocks.Client_Get_Call {
+	return clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+}
+
+// mockGetAccountInfos returns the AccountInfo of the cluster in the context
+func mockGetAccountInfos(clientMock *mocks.Client, specs map[string]v1alpha1.AccountInfoSpec) *mocks.Client_Get_Call {
+	return clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			spec, ok := specs[cluster.String()]
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			*obj.(*v1alpha1.AccountInfo) = v1alpha1.AccountInfo{ObjectMeta: metav1.ObjectMeta{Name: key.Name}, Spec: *spec.DeepCopy()}
+			return nil
+		})
+}
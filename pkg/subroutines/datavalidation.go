@@ -0,0 +1,71 @@
+package subroutines
+
+import (
+	"context"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/pkg/dataschema"
+)
+
+var _ subroutine.Subroutine = (*DataValidationSubroutine)(nil)
+
+const DataValidationSubroutineName = "DataValidationSubroutine"
+
+// DataValidationSubroutine validates spec.data against the schema registered for the account type and reports the
+// result in the DataValid condition. Invalid data does not block the reconciliation, the webhook only rejects changes of
+// spec.data, this catches accounts that were stored before a schema was registered or changed.
+type DataValidationSubroutine struct {
+	schemas *dataschema.Registry
+}
+
+func NewDataValidationSubroutine(schemas *dataschema.Registry) *DataValidationSubroutine {
+	return &DataValidationSubroutine{schemas: schemas}
+}
+
+func (r *DataValidationSubroutine) GetName() string {
+	return DataValidationSubroutineName
+}
+
+func (r *DataValidationSubroutine) Finalizers() []string { // coverage-ignore
+	return []string{}
+}
+
+func (r *DataValidationSubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (r *DataValidationSubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+	log := logger.LoadLoggerFromContext(ctx)
+
+	if !r.schemas.HasSchema(string(instance.Spec.Type)) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionDataValid)
+		return ctrl.Result{}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionDataValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.DataReasonValid,
+		Message:            "The data matches the schema of the account type",
+		ObservedGeneration: instance.GetGeneration(),
+	}
+	if errs := r.schemas.Validate(field.NewPath("spec", "data"), string(instance.Spec.Type), instance.Spec.Data); len(errs) > 0 {
+		log.Info().Err(errs.ToAggregate()).Msg("account data does not match the schema")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.DataReasonInvalid
+		condition.Message = errs.ToAggregate().Error()
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,119 @@
+package subroutines_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	openmfpcontext "github.com/platform-mesh/golang-commons/context"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/stretchr/testify/suite"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
+	"github.com/openmfp/account-operator/pkg/dataschema"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+)
+
+type DataValidationSubroutineTestSuite struct {
+	suite.Suite
+
+	// Tested Object(s)
+	testObj *subroutines.DataValidationSubroutine
+
+	context context.Context
+	log     *logger.Logger
+}
+
+func (suite *DataValidationSubroutineTestSuite) SetupTest() {
+	schemas, err := dataschema.New(map[string]apiextensionsv1.JSONSchemaProps{
+		string(v1alpha1.AccountTypeOrg): {
+			Type:     "object",
+			Required: []string{"costCenter"},
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"costCenter": {Type: "string"},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Initialize Tested Object(s)
+	suite.testObj = subroutines.NewDataValidationSubroutine(schemas)
+
+	suite.log, err = logger.New(logger.DefaultConfig())
+	suite.Require().NoError(err)
+	suite.context, _, _ = openmfpcontext.StartContext(suite.log, config.OperatorConfig{}, 1*time.Minute)
+}
+
+func TestDataValidationSubroutineTestSuite(t *testing.T) {
+	suite.Run(t, new(DataValidationSubroutineTestSuite))
+}
+
+func (suite *DataValidationSubroutineTestSuite) TestGetName_OK() {
+	suite.Equal(subroutines.DataValidationSubroutineName, suite.testObj.GetName())
+}
+
+func (suite *DataValidationSubroutineTestSuite) TestProcessing_Valid() {
+	// Given
+	testAccount := &v1alpha1.Account{Spec: v1alpha1.AccountSpec{
+		Type: v1alpha1.AccountTypeOrg,
+		Data: &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)},
+	}}
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	condition := meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionDataValid)
+	suite.Require().NotNil(condition)
+	suite.Equal(metav1.ConditionTrue, condition.Status)
+	suite.Equal(v1alpha1.DataReasonValid, condition.Reason)
+}
+
+func (suite *DataValidationSubroutineTestSuite) TestProcessing_Invalid() {
+	// Given
+	testAccount := &v1alpha1.Account{Spec: v1alpha1.AccountSpec{
+		Type: v1alpha1.AccountTypeOrg,
+		Data: &apiextensionsv1.JSON{Raw: []byte(`{"costCentre":"cc-1"}`)},
+	}}
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	condition := meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionDataValid)
+	suite.Require().NotNil(condition)
+	suite.Equal(metav1.ConditionFalse, condition.Status)
+	suite.Equal(v1alpha1.DataReasonInvalid, condition.Reason)
+	suite.Contains(condition.Message, "spec.data.costCenter")
+}
+
+func (suite *DataValidationSubroutineTestSuite) TestProcessing_No_Schema() {
+	// Given
+	testAccount := &v1alpha1.Account{Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount}}
+	meta.SetStatusCondition(&testAccount.Status.Conditions, metav1.Condition{Type: v1alpha1.ConditionDataValid, Status: metav1.ConditionFalse, Reason: v1alpha1.DataReasonInvalid})
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Nil(meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionDataValid))
+}
+
+func (suite *DataValidationSubroutineTestSuite) TestFinalize_OK() {
+	// When
+	res, err := suite.testObj.Finalize(suite.context, &v1alpha1.Account{})
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+}
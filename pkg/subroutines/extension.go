@@ -0,0 +1,567 @@
+package subroutines
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	kcpcorev1alpha "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	commonconfig "github.com/platform-mesh/golang-commons/config"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
+	"github.com/openmfp/account-operator/pkg/templating"
+)
+
+var _ subroutine.Subroutine = (*ExtensionSubroutine)(nil)
+
+const (
+	ExtensionSubroutineName      = "ExtensionSubroutine"
+	ExtensionSubroutineFinalizer = "account.core.openmfp.org/extension"
+	// ExtensionFieldManager is the field manager extension objects are applied with
+	ExtensionFieldManager = "account-operator-extensions"
+	// ExtensionRenderedHashAnnotation holds the hash of the rendered state an extension object was last applied with
+	ExtensionRenderedHashAnnotation = "account.core.openmfp.org/rendered-hash"
+)
+
+// ExtensionSubroutine renders the extensions of an account and applies the resulting objects to the account workspace
+// with server side apply. Manual changes of managed fields are reverted on every reconcile. The account is only
+// considered ready once every extension object reports its ready condition. Objects that are no longer rendered, because
+// their extension was removed or renders a different object, are pruned according to their deletion policy.
+type ExtensionSubroutine struct {
+	client  client.Client
+	limiter workqueue.TypedRateLimiter[ClusteredName]
+}
+
+func NewExtensionSubroutine(client client.Client) *ExtensionSubroutine {
+	exp := workqueue.NewTypedItemExponentialFailureRateLimiter[ClusteredName](1*time.Second, 120*time.Second)
+	return &ExtensionSubroutine{client: client, limiter: exp}
+}
+
+func (r *ExtensionSubroutine) GetName() string {
+	return ExtensionSubroutineName
+}
+
+func (r *ExtensionSubroutine) Finalizers() []string { // coverage-ignore
+	return []string{ExtensionSubroutineFinalizer}
+}
+
+func (r *ExtensionSubroutine) Finalize(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+	log := logger.LoadLoggerFromContext(ctx)
+	cn := MustGetClusteredName(ctx, ro)
+
+	if len(instance.Spec.Extensions) == 0 {
+		r.limiter.Forget(cn)
+		return ctrl.Result{}, nil
+	}
+
+	// deleted accounts keep their extension objects until the retention period of the trash expired, the finalizer of a
+	// disabled trash is released by the workspace subroutine
+	cfg, _ := commonconfig.LoadConfigFromContext(ctx).(config.OperatorConfig)
+	if cfg.Trash.RetentionPeriod > 0 && controllerutil.ContainsFinalizer(instance, TrashSubroutineFinalizer) {
+		return ctrl.Result{RequeueAfter: r.limiter.When(cn)}, nil
+	}
+
+	accountWorkspace := &kcptenancyv1alpha.Workspace{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: instance.Name}, accountWorkspace)
+	if kerrors.IsNotFound(err) {
+		// extension objects are gone together with the workspace
+		r.limiter.Forget(cn)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	if accountWorkspace.Spec.Cluster == "" {
+		// the workspace was never scheduled, so no extension object could have been created
+		r.limiter.Forget(cn)
+		return ctrl.Result{}, nil
+	}
+
+	wsCtx := kontext.WithCluster(ctx, logicalcluster.Name(accountWorkspace.Spec.Cluster))
+
+	// a missing AccountInfo must not block the deletion, templates referencing it fail to render and are skipped
+	data, _, err := r.templateData(ctx, wsCtx, instance)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	// Extensions are removed in reverse declaration order, each one has to be gone before the next one is deleted
+	for i := len(instance.Spec.Extensions) - 1; i >= 0; i-- {
+		extension := instance.Spec.Extensions[i]
+		if extension.DeletionPolicy == v1alpha1.ExtensionDeletionPolicyOrphan {
+			continue
+		}
+
+		desired, renderErr := renderExtension(instance, i, data)
+		if renderErr != nil {
+			// an extension that can not be rendered can not be located, blocking the deletion would not help
+			log.Error().Err(renderErr).Int("extension", i).Msg("failed to render extension, skipping deletion")
+			continue
+		}
+
+		deleted, err := r.deleteExtension(wsCtx, desired)
+		if err != nil {
+			log.Error().Err(err).Int("extension", i).Str("kind", desired.GetKind()).Str("name", desired.GetName()).Msg("failed to delete extension")
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+
+		if !deleted {
+			log.Info().Str("kind", desired.GetKind()).Str("name", desired.GetName()).Msg("extension is not deleted yet, retry")
+			next := r.limiter.When(cn)
+			return ctrl.Result{RequeueAfter: next}, nil
+		}
+	}
+
+	r.limiter.Forget(cn)
+	return ctrl.Result{}, nil
+}
+
+func (r *ExtensionSubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+	log := logger.LoadLoggerFromContext(ctx)
+	cn := MustGetClusteredName(ctx, ro)
+
+	previous := instance.Status.Extensions
+	if len(instance.Spec.Extensions) == 0 && len(removedExtensions(previous, nil)) == 0 {
+		instance.Status.Extensions = nil
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionExtensionsReady)
+		r.limiter.Forget(cn)
+		return ctrl.Result{}, nil
+	}
+
+	statuses := make([]v1alpha1.ExtensionStatus, len(instance.Spec.Extensions))
+	for i, extension := range instance.Spec.Extensions {
+		statuses[i] = v1alpha1.ExtensionStatus{TypeMeta: extension.TypeMeta, Phase: v1alpha1.ExtensionPhasePending, DeletionPolicy: extension.DeletionPolicy}
+		// the object keeps its previous name until the extension is rendered again
+		if i < len(previous) && previous[i].TypeMeta == extension.TypeMeta && previous[i].Phase != v1alpha1.ExtensionPhaseRemoved {
+			statuses[i].Name = previous[i].Name
+			statuses[i].Namespace = previous[i].Namespace
+		}
+	}
+	// objects that are no longer rendered stay in the status until they are pruned, so they are not lost if an
+	// extension fails before all extensions are rendered
+	instance.Status.Extensions = append(statuses, removedExtensions(previous, statuses)...)
+	statuses = instance.Status.Extensions[:len(statuses)]
+
+	accountWorkspace, err := retrieveWorkspace(ctx, instance, r.client, log)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	if accountWorkspace.Status.Phase != kcpcorev1alpha.LogicalClusterPhaseReady {
+		log.Info().Msg("workspace is not ready yet, retry")
+		setExtensionsCondition(instance, metav1.ConditionUnknown, v1alpha1.ExtensionReasonWorkspaceNotReady, "The account workspace is not ready yet")
+		next := r.limiter.When(cn)
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	// Prepare context to work in workspace
+	wsCtx := kontext.WithCluster(ctx, logicalcluster.Name(accountWorkspace.Spec.Cluster))
+
+	data, found, err := r.templateData(ctx, wsCtx, instance)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	if !found {
+		log.Info().Msg("accountInfo does not yet exist, retry")
+		setExtensionsCondition(instance, metav1.ConditionUnknown, v1alpha1.ExtensionReasonWorkspaceNotReady, "The AccountInfo of the account workspace does not exist yet")
+		next := r.limiter.When(cn)
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	var notReady []string
+	for i, extension := range instance.Spec.Extensions {
+		status := &statuses[i]
+
+		desired, renderErr := renderExtension(instance, i, data)
+		if renderErr != nil {
+			log.Error().Err(renderErr).Int("extension", i).Msg("failed to render extension")
+			status.Phase = v1alpha1.ExtensionPhaseFailed
+			status.LastError = renderErr.Error()
+			setExtensionsFailure(ctx, instance, v1alpha1.ExtensionReasonRenderFailed, renderErr.Error())
+			return ctrl.Result{}, errors.NewOperatorError(renderErr, false, false)
+		}
+		status.Name = desired.GetName()
+		status.Namespace = desired.GetNamespace()
+		if i < len(previous) && previous[i].Kind == status.Kind && previous[i].Name == status.Name {
+			status.LastDriftCorrectionTime = previous[i].LastDriftCorrectionTime
+		}
+
+		current, drifted, err := r.applyExtension(wsCtx, extension, desired)
+		if err != nil {
+			log.Error().Err(err).Int("extension", i).Str("kind", desired.GetKind()).Str("name", desired.GetName()).Msg("failed to apply extension")
+			status.Phase = v1alpha1.ExtensionPhaseFailed
+			status.LastError = err.Error()
+			setExtensionsFailure(ctx, instance, v1alpha1.ExtensionReasonApplyFailed, fmt.Sprintf("Extension %s %s could not be applied: %s", desired.GetKind(), desired.GetName(), err))
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+		if drifted {
+			log.Info().Str("kind", desired.GetKind()).Str("name", desired.GetName()).Msg("reverted manual changes of extension")
+			status.LastDriftCorrectionTime = &metav1.Time{Time: time.Now()}
+		}
+
+		ready, condition := extensionReadiness(extension, current)
+		status.ReadyCondition = condition
+		if !ready {
+			log.Info().Str("kind", current.GetKind()).Str("name", current.GetName()).Msg("extension is not ready yet, retry")
+			status.Phase = v1alpha1.ExtensionPhaseApplied
+			notReady = append(notReady, fmt.Sprintf("%s %s", desired.GetKind(), desired.GetName()))
+			continue
+		}
+		status.Phase = v1alpha1.ExtensionPhaseReady
+	}
+
+	// all extensions are rendered, the objects they no longer render are pruned
+	removed, err := r.pruneExtensions(wsCtx, removedExtensions(previous, statuses))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to prune removed extensions")
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	instance.Status.Extensions = append(statuses, removed...)
+
+	if len(notReady) > 0 {
+		setExtensionsFailure(ctx, instance, v1alpha1.ExtensionReasonNotReady, fmt.Sprintf("Waiting for extensions to become ready: %s", strings.Join(notReady, ", ")))
+		next := r.limiter.When(cn)
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	if len(removed) > 0 {
+		names := make([]string, len(removed))
+		for i, status := range removed {
+			names[i] = fmt.Sprintf("%s %s", status.Kind, status.Name)
+		}
+		setExtensionsFailure(ctx, instance, v1alpha1.ExtensionReasonNotReady, fmt.Sprintf("Waiting for removed extensions to be deleted: %s", strings.Join(names, ", ")))
+		next := r.limiter.When(cn)
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
+
+	if len(instance.Spec.Extensions) == 0 {
+		instance.Status.Extensions = nil
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionExtensionsReady)
+		r.limiter.Forget(cn)
+		return ctrl.Result{}, nil
+	}
+
+	setExtensionsCondition(instance, metav1.ConditionTrue, v1alpha1.ExtensionReasonReady, "All extensions are ready")
+	r.limiter.Forget(cn)
+	return ctrl.Result{}, nil
+}
+
+// applyExtension applies the desired object with server side apply and returns the object as it is stored in the
+// workspace. Objects of create-only extensions are only applied if they do not exist yet. The returned bool reports
+// whether the existing object was changed by someone else since it was last applied with the same rendered state.
+func (r *ExtensionSubroutine) applyExtension(ctx context.Context, extension v1alpha1.Extension, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, false, err
+	}
+	exists := err == nil
+
+	if exists && extension.CreateOnly {
+		return current, false, nil
+	}
+
+	hash, err := renderedHash(desired)
+	if err != nil {
+		return nil, false, err
+	}
+	// a different hash means the account changed, that is an update and not a drift
+	drifted := exists && current.GetAnnotations()[ExtensionRenderedHashAnnotation] == hash && !containsFields(desired.Object, current.Object)
+
+	applied := desired.DeepCopy()
+	applied.SetAnnotations(mergeStringMaps(applied.GetAnnotations(), map[string]string{ExtensionRenderedHashAnnotation: hash}))
+	err = r.client.Patch(ctx, applied, client.Apply, client.FieldOwner(ExtensionFieldManager), client.ForceOwnership)
+	return applied, drifted, err
+}
+
+func renderedHash(desired *unstructured.Unstructured) (string, error) {
+	raw, err := json.Marshal(desired.Object)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// containsFields reports whether every field set in desired is set to the same value in actual.
+func containsFields(desired, actual any) bool {
+	switch d := desired.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			if !containsFields(value, a[key]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !containsFields(d[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, actual)
+	}
+}
+
+// deleteExtension triggers the deletion of the extension object and reports whether the object is gone.
+func (r *ExtensionSubroutine) deleteExtension(ctx context.Context, desired *unstructured.Unstructured) (bool, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(desired.GroupVersionKind())
+
+	err := r.client.Get(ctx, client.ObjectKey{Name: desired.GetName(), Namespace: desired.GetNamespace()}, obj)
+	if kerrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if obj.GetDeletionTimestamp() == nil {
+		err = r.client.Delete(ctx, obj)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// pruneExtensions deletes the objects of removed extensions, unless their deletion policy orphans them, and returns the
+// ones that are not gone yet
+func (r *ExtensionSubroutine) pruneExtensions(ctx context.Context, removed []v1alpha1.ExtensionStatus) ([]v1alpha1.ExtensionStatus, error) {
+	var pending []v1alpha1.ExtensionStatus
+	for _, status := range removed {
+		if status.DeletionPolicy == v1alpha1.ExtensionDeletionPolicyOrphan {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(status.GroupVersionKind())
+		obj.SetName(status.Name)
+		obj.SetNamespace(status.Namespace)
+		deleted, err := r.deleteExtension(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			pending = append(pending, status)
+		}
+	}
+	return pending, nil
+}
+
+// removedExtensions returns the objects of the previous extension statuses that none of the current statuses refers to
+func removedExtensions(previous, current []v1alpha1.ExtensionStatus) []v1alpha1.ExtensionStatus {
+	var removed []v1alpha1.ExtensionStatus
+	for _, status := range previous {
+		if status.Name == "" {
+			continue
+		}
+		if slices.ContainsFunc(current, func(c v1alpha1.ExtensionStatus) bool {
+			return c.GroupVersionKind().GroupKind() == status.GroupVersionKind().GroupKind() && c.Namespace == status.Namespace && c.Name == status.Name
+		}) {
+			continue
+		}
+		status.Phase = v1alpha1.ExtensionPhaseRemoved
+		status.LastError = ""
+		status.ReadyCondition = nil
+		removed = append(removed, status)
+	}
+	return removed
+}
+
+// templateData builds the context the go templates of an extension are executed against. The AccountInfo is read from
+// the account workspace, if it does not exist yet an empty AccountInfo is used.
+func (r *ExtensionSubroutine) templateData(ctx context.Context, wsCtx context.Context, instance *v1alpha1.Account) (*templating.Data, bool, error) {
+	account, err := runtime.DefaultUnstructuredConverter.ToUnstructured(instance)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data := &templating.Data{Account: account}
+	if cfg, ok := commonconfig.LoadConfigFromContext(ctx).(config.OperatorConfig); ok {
+		data.Config = templating.Config{Kcp: templating.KcpConfig{ProviderWorkspace: cfg.Kcp.ProviderWorkspace}}
+	}
+
+	if instance.Spec.Data != nil && len(instance.Spec.Data.Raw) > 0 {
+		if err := json.Unmarshal(instance.Spec.Data.Raw, &data.Data); err != nil {
+			return nil, false, err
+		}
+	}
+
+	accountInfo := &v1alpha1.AccountInfo{}
+	err = r.client.Get(wsCtx, client.ObjectKey{Name: DefaultAccountInfoName}, accountInfo)
+	if kerrors.IsNotFound(err) {
+		return data, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	data.AccountInfo = templating.AccountInfo{
+		Account:      templateLocation(accountInfo.Spec.Account),
+		Organization: templateLocation(accountInfo.Spec.Organization),
+		FGAStoreID:   accountInfo.Spec.FGA.Store.Id,
+	}
+	if accountInfo.Spec.ParentAccount != nil {
+		parent := templateLocation(*accountInfo.Spec.ParentAccount)
+		data.AccountInfo.Parent = &parent
+	}
+	return data, true, nil
+}
+
+func templateLocation(location v1alpha1.AccountLocation) templating.Location {
+	return templating.Location{
+		Name:            location.Name,
+		Type:            string(location.Type),
+		ClusterID:       location.GeneratedClusterId,
+		OriginClusterID: location.OriginClusterId,
+		Path:            location.Path,
+		URL:             location.URL,
+		DisplayName:     location.DisplayName,
+	}
+}
+
+// renderExtension renders the metadata and spec templates of the extension at the given index into an unstructured
+// object. If the metadata template does not define a name, the object is named after the account.
+func renderExtension(instance *v1alpha1.Account, index int, data *templating.Data) (*unstructured.Unstructured, *field.Error) {
+	extension := instance.Spec.Extensions[index]
+	path := field.NewPath("spec", "extensions").Index(index)
+
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetGroupVersionKind(extension.GroupVersionKind())
+
+	if len(extension.MetadataGoTemplate.Raw) > 0 {
+		metadata, err := templating.Render(path.Child("metadataGoTemplate"), extension.MetadataGoTemplate.Raw, data)
+		if err != nil {
+			return nil, err
+		}
+		obj.Object["metadata"] = metadata
+	}
+
+	if len(extension.SpecGoTemplate.Raw) > 0 {
+		spec, err := templating.Render(path.Child("specGoTemplate"), extension.SpecGoTemplate.Raw, data)
+		if err != nil {
+			return nil, err
+		}
+		obj.Object["spec"] = spec
+	}
+
+	if obj.GetName() == "" {
+		obj.SetName(instance.Name)
+	}
+	return obj, nil
+}
+
+// extensionReadiness checks the ready condition configured on the extension and returns the observed condition.
+// Extensions without a ready condition type are considered ready as soon as they exist.
+func extensionReadiness(extension v1alpha1.Extension, obj *unstructured.Unstructured) (bool, *v1alpha1.ObservedCondition) {
+	if extension.ReadyConditionType == nil || *extension.ReadyConditionType == "" {
+		return true, nil
+	}
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return false, nil
+	}
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != *extension.ReadyConditionType {
+			continue
+		}
+
+		observed := &v1alpha1.ObservedCondition{Type: *extension.ReadyConditionType}
+		observed.Status, _, _ = unstructured.NestedString(condition, "status")
+		observed.Reason, _, _ = unstructured.NestedString(condition, "reason")
+		observed.Message, _, _ = unstructured.NestedString(condition, "message")
+		if lastTransition, ok, _ := unstructured.NestedString(condition, "lastTransitionTime"); ok {
+			if t, err := time.Parse(time.RFC3339, lastTransition); err == nil {
+				observed.LastTransitionTime = &metav1.Time{Time: t}
+			}
+		}
+		return observed.Status == string(metav1.ConditionTrue), observed
+	}
+	return false, nil
+}
+
+func setExtensionsCondition(instance *v1alpha1.Account, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionExtensionsReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.GetGeneration(),
+	})
+}
+
+type extensionFailureKey struct{}
+
+// WithExtensionFailure prepares the context of a reconcile for the ExtensionSubroutine to record why the extensions of
+// the account failed. The returned function returns the ExtensionsReady condition of the failure, nil if the extensions
+// did not fail in the reconcile.
+func WithExtensionFailure(ctx context.Context) (context.Context, func() *metav1.Condition) {
+	var failure *metav1.Condition
+	return context.WithValue(ctx, extensionFailureKey{}, &failure), func() *metav1.Condition { return failure }
+}
+
+// setExtensionsFailure sets the ExtensionsReady condition to False and records it as failure of the reconcile
+func setExtensionsFailure(ctx context.Context, instance *v1alpha1.Account, reason, message string) {
+	setExtensionsCondition(instance, metav1.ConditionFalse, reason, message)
+	if failure, ok := ctx.Value(extensionFailureKey{}).(**metav1.Condition); ok {
+		*failure = meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionExtensionsReady).DeepCopy()
+	}
+}
+
+func mergeStringMaps(existing, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return existing
+	}
+	if existing == nil {
+		existing = make(map[string]string, len(desired))
+	}
+	for k, v := range desired {
+		existing[k] = v
+	}
+	return existing
+}
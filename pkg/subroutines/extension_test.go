@@ -0,0 +1,760 @@
+package subroutines_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	kcpcorev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	openmfpcontext "github.com/platform-mesh/golang-commons/context"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
+)
+
+type ExtensionSubroutineTestSuite struct {
+	suite.Suite
+
+	// Tested Object(s)
+	testObj *subroutines.ExtensionSubroutine
+
+	// Mocks
+	clientMock *mocks.Client
+	context    context.Context
+	log        *logger.Logger
+}
+
+func (suite *ExtensionSubroutineTestSuite) SetupTest() {
+	// Setup Mocks
+	suite.clientMock = new(mocks.Client)
+
+	// Initialize Tested Object(s)
+	suite.testObj = subroutines.NewExtensionSubroutine(suite.clientMock)
+
+	cfg := config.OperatorConfig{}
+	var err error
+	suite.log, err = logger.New(logger.DefaultConfig())
+	suite.Require().NoError(err)
+	suite.context, _, _ = openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+	suite.context = kontext.WithCluster(suite.context, "some-cluster-id")
+}
+
+func TestExtensionSubroutineTestSuite(t *testing.T) {
+	suite.Run(t, new(ExtensionSubroutineTestSuite))
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestGetName_OK() {
+	// When
+	result := suite.testObj.GetName()
+
+	// Then
+	suite.Equal(subroutines.ExtensionSubroutineName, result)
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_OK_No_Extensions() {
+	// Given
+	testAccount := newExtensionTestAccount()
+	testAccount.Status.Extensions = []v1alpha1.ExtensionStatus{{Phase: v1alpha1.ExtensionPhaseReady}}
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Nil(testAccount.Status.Extensions)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_No_Cluster() {
+	// Given
+	testAccount := newExtensionTestAccount()
+
+	// When
+	assert.Panics(suite.T(), func() {
+		_, _ = suite.testObj.Process(context.Background(), testAccount)
+	})
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Workspace_Not_Ready() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseInitializing, "root:orgs:test")
+	ctx, extensionFailure := subroutines.WithExtensionFailure(suite.context)
+
+	// When
+	res, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.Require().Len(testAccount.Status.Extensions, 1)
+	suite.Equal(v1alpha1.ExtensionPhasePending, testAccount.Status.Extensions[0].Phase)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionUnknown, v1alpha1.ExtensionReasonWorkspaceNotReady)
+	suite.Nil(extensionFailure())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Workspace_Error() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewInternalError(fmt.Errorf("failed")))
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_OK_Create() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"owner":"{{ .Account.metadata.name }}","replicas":2}`, nil))
+	testAccount.Spec.Extensions[0].MetadataGoTemplate = apiextensionsv1.JSON{Raw: []byte(`{"labels":{"account":"{{ .Account.spec.type }}"}}`)}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+
+	var created *unstructured.Unstructured
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
+			created = obj.(*unstructured.Unstructured)
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Require().NotNil(created)
+	suite.Equal("test-account", created.GetName())
+	suite.Equal("ExampleExtension", created.GetKind())
+	suite.Equal(map[string]string{"account": "account"}, created.GetLabels())
+	suite.Equal(map[string]any{"owner": "test-account", "replicas": int64(2)}, created.Object["spec"])
+	suite.Require().Len(testAccount.Status.Extensions, 1)
+	suite.Equal(v1alpha1.ExtensionPhaseReady, testAccount.Status.Extensions[0].Phase)
+	suite.Equal("test-account", testAccount.Status.Extensions[0].Name)
+	suite.Equal("ExampleExtension", testAccount.Status.Extensions[0].Kind)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionTrue, v1alpha1.ExtensionReasonReady)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_OK_Update() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"owner":"{{ .Account.metadata.name }}"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtension(map[string]any{"owner": "someone-else"}, nil)
+
+	var updated *unstructured.Unstructured
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
+			updated = obj.(*unstructured.Unstructured)
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Require().NotNil(updated)
+	suite.Equal(map[string]any{"owner": "test-account"}, updated.Object["spec"])
+	suite.NotEmpty(updated.GetAnnotations()[subroutines.ExtensionRenderedHashAnnotation])
+	suite.Nil(testAccount.Status.Extensions[0].LastDriftCorrectionTime)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Repairs_Drift() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"owner":"{{ .Account.metadata.name }}"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound().Once()
+	var applied *unstructured.Unstructured
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
+			applied = obj.(*unstructured.Unstructured).DeepCopy()
+		}).
+		Return(nil)
+	_, err := suite.testObj.Process(suite.context, testAccount)
+	suite.Require().Nil(err)
+	suite.Require().NotNil(applied)
+	suite.Nil(testAccount.Status.Extensions[0].LastDriftCorrectionTime)
+
+	// someone changed the owner by hand
+	drifted := applied.DeepCopy()
+	drifted.Object["spec"] = map[string]any{"owner": "someone-else"}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			drifted.DeepCopyInto(obj.(*unstructured.Unstructured))
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Equal(map[string]any{"owner": "test-account"}, applied.Object["spec"])
+	suite.NotNil(testAccount.Status.Extensions[0].LastDriftCorrectionTime)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_CreateOnly_Existing() {
+	// Given
+	extension := newTestExtension(`{"owner":"{{ .Account.metadata.name }}"}`, nil)
+	extension.CreateOnly = true
+	testAccount := newExtensionTestAccount(extension)
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtension(map[string]any{"owner": "someone-else"}, nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Equal(v1alpha1.ExtensionPhaseReady, testAccount.Status.Extensions[0].Phase)
+	suite.clientMock.AssertNotCalled(suite.T(), "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_CreateOnly_Not_Existing() {
+	// Given
+	extension := newTestExtension(`{"owner":"{{ .Account.metadata.name }}"}`, nil)
+	extension.CreateOnly = true
+	testAccount := newExtensionTestAccount(extension)
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+	suite.mockPatchExtension(nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_OK_Template_Data() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"org":"{{ .AccountInfo.Organization.Name }}","parent":"{{ .AccountInfo.Parent.Name }}","store":"{{ .AccountInfo.FGAStoreID }}","costCenter":"{{ index .Data \"costCenter\" | upper }}","tier":"{{ index .Data \"tier\" | default \"basic\" }}"}`, nil))
+	testAccount.Spec.Data = &apiextensionsv1.JSON{Raw: []byte(`{"costCenter":"cc-1"}`)}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+
+	var created *unstructured.Unstructured
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
+			created = obj.(*unstructured.Unstructured)
+		}).
+		Return(nil)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Require().NotNil(created)
+	suite.Equal(map[string]any{
+		"org":        "root-org",
+		"parent":     "parent-account",
+		"store":      "store-id",
+		"costCenter": "CC-1",
+		"tier":       "basic",
+	}, created.Object["spec"])
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_AccountInfo_Not_Existing() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionUnknown, v1alpha1.ExtensionReasonWorkspaceNotReady)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Create_Error() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Return(kerrors.NewBadRequest("failed"))
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.True(err.Sentry())
+	suite.Require().Len(testAccount.Status.Extensions, 1)
+	suite.Equal(v1alpha1.ExtensionPhaseFailed, testAccount.Status.Extensions[0].Phase)
+	suite.Equal("failed", testAccount.Status.Extensions[0].LastError)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionFalse, v1alpha1.ExtensionReasonApplyFailed)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Invalid_Template() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"{{ .Account.metadata.name "}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	ctx, extensionFailure := subroutines.WithExtensionFailure(suite.context)
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.Require().Len(testAccount.Status.Extensions, 1)
+	suite.Equal(v1alpha1.ExtensionPhaseFailed, testAccount.Status.Extensions[0].Phase)
+	suite.Contains(testAccount.Status.Extensions[0].LastError, "spec.extensions[0].specGoTemplate.foo")
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionFalse, v1alpha1.ExtensionReasonRenderFailed)
+	suite.Require().NotNil(extensionFailure())
+	suite.Equal(v1alpha1.ExtensionReasonRenderFailed, extensionFailure().Reason)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Waits_For_Ready_Condition() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, ptr.To("Ready")))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+	suite.mockPatchExtension([]any{
+		map[string]any{"type": "Ready", "status": "False", "reason": "Provisioning", "lastTransitionTime": "2025-01-01T00:00:00Z"},
+	})
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.Require().Len(testAccount.Status.Extensions, 1)
+	status := testAccount.Status.Extensions[0]
+	suite.Equal(v1alpha1.ExtensionPhaseApplied, status.Phase)
+	suite.Require().NotNil(status.ReadyCondition)
+	suite.Equal("False", status.ReadyCondition.Status)
+	suite.Equal("Provisioning", status.ReadyCondition.Reason)
+	suite.NotNil(status.ReadyCondition.LastTransitionTime)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionFalse, v1alpha1.ExtensionReasonNotReady)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_OK_Ready_Condition() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, ptr.To("Ready")))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+	suite.mockPatchExtension([]any{
+		map[string]any{"type": "Synced", "status": "False"},
+		map[string]any{"type": "Ready", "status": "True"},
+	})
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Prunes_Removed_Extension() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	testAccount.Status.Extensions = []v1alpha1.ExtensionStatus{
+		{TypeMeta: testAccount.Spec.Extensions[0].TypeMeta, Name: "test-account", Phase: v1alpha1.ExtensionPhaseReady},
+		newRemovedExtensionStatus(v1alpha1.ExtensionDeletionPolicyDelete),
+	}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound().Once()
+	suite.mockPatchExtension(nil)
+	suite.mockGetExtension(map[string]any{"foo": "bar"}, nil).Once()
+
+	var deleted *unstructured.Unstructured
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Run(func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) {
+			deleted = obj.(*unstructured.Unstructured)
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.Require().NotNil(deleted)
+	suite.Equal("OtherExtension", deleted.GetKind())
+	suite.Equal("removed", deleted.GetName())
+	suite.Require().Len(testAccount.Status.Extensions, 2)
+	suite.Equal(v1alpha1.ExtensionPhaseReady, testAccount.Status.Extensions[0].Phase)
+	suite.Equal(v1alpha1.ExtensionPhaseRemoved, testAccount.Status.Extensions[1].Phase)
+	suite.Equal("removed", testAccount.Status.Extensions[1].Name)
+	suite.verifyExtensionsCondition(testAccount, metav1.ConditionFalse, v1alpha1.ExtensionReasonNotReady)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Removed_Extension_Deleted() {
+	// Given
+	testAccount := newExtensionTestAccount()
+	testAccount.Status.Extensions = []v1alpha1.ExtensionStatus{newRemovedExtensionStatus(v1alpha1.ExtensionDeletionPolicyDelete)}
+	testAccount.Status.Conditions = []metav1.Condition{{Type: v1alpha1.ConditionExtensionsReady, Status: metav1.ConditionFalse, Reason: v1alpha1.ExtensionReasonNotReady}}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound()
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Nil(testAccount.Status.Extensions)
+	suite.Nil(meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionExtensionsReady))
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Orphans_Removed_Extension() {
+	// Given
+	testAccount := newExtensionTestAccount()
+	testAccount.Status.Extensions = []v1alpha1.ExtensionStatus{newRemovedExtensionStatus(v1alpha1.ExtensionDeletionPolicyOrphan)}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+
+	// When
+	res, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Nil(testAccount.Status.Extensions)
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestProcessing_Keeps_Removed_Extension_On_Failure() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"{{ .Account.metadata.name "}`, nil))
+	testAccount.Status.Extensions = []v1alpha1.ExtensionStatus{newRemovedExtensionStatus(v1alpha1.ExtensionDeletionPolicyDelete)}
+	testAccount.Status.Extensions[0].Phase = v1alpha1.ExtensionPhaseReady
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.Require().Len(testAccount.Status.Extensions, 2)
+	suite.Equal(v1alpha1.ExtensionPhaseFailed, testAccount.Status.Extensions[0].Phase)
+	suite.Equal(v1alpha1.ExtensionPhaseRemoved, testAccount.Status.Extensions[1].Phase)
+	suite.Equal("removed", testAccount.Status.Extensions[1].Name)
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestGetFinalizerName() {
+	// When
+	finalizers := suite.testObj.Finalizers()
+
+	// Then
+	suite.Equal([]string{subroutines.ExtensionSubroutineFinalizer}, finalizers)
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_OK_No_Extensions() {
+	// Given
+	testAccount := newExtensionTestAccount()
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_OK_Workspace_NotExisting() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_Waits_For_Trash_Finalizer() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	testAccount.SetFinalizers([]string{subroutines.ExtensionSubroutineFinalizer, subroutines.TrashSubroutineFinalizer})
+	cfg := config.OperatorConfig{}
+	cfg.Trash.RetentionPeriod = time.Hour
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+	// When
+	res, err := suite.testObj.Finalize(kontext.WithCluster(ctx, "some-cluster-id"), testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_Ignores_Trash_Finalizer_When_Trash_Disabled() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	testAccount.SetFinalizers([]string{subroutines.ExtensionSubroutineFinalizer, subroutines.TrashSubroutineFinalizer})
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_Deletes_In_Reverse_Order() {
+	// Given
+	first := newTestExtension(`{"foo":"bar"}`, nil)
+	first.MetadataGoTemplate = apiextensionsv1.JSON{Raw: []byte(`{"name":"first"}`)}
+	second := newTestExtension(`{"foo":"bar"}`, nil)
+	second.MetadataGoTemplate = apiextensionsv1.JSON{Raw: []byte(`{"name":"second"}`)}
+	testAccount := newExtensionTestAccount(first, second)
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtension(map[string]any{"foo": "bar"}, nil).Once()
+
+	var deleted []string
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Run(func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) {
+			deleted = append(deleted, obj.GetName())
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.Equal([]string{"second"}, deleted)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_OK_All_Deleted() {
+	// Given
+	orphaned := newTestExtension(`{"foo":"bar"}`, nil)
+	orphaned.DeletionPolicy = v1alpha1.ExtensionDeletionPolicyOrphan
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil), orphaned)
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtensionNotFound().Once()
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_Waits_For_Deletion() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual := obj.(*unstructured.Unstructured)
+			actual.SetName(key.Name)
+			actual.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		}).
+		Return(nil)
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) TestFinalize_Delete_Error() {
+	// Given
+	testAccount := newExtensionTestAccount(newTestExtension(`{"foo":"bar"}`, nil))
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:test")
+	suite.mockGetAccountInfo()
+	suite.mockGetExtension(map[string]any{"foo": "bar"}, nil)
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Return(kerrors.NewInternalError(fmt.Errorf("failed")))
+
+	// When
+	_, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *ExtensionSubroutineTestSuite) verifyExtensionsCondition(account *v1alpha1.Account, status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(account.Status.Conditions, v1alpha1.ConditionExtensionsReady)
+	suite.Require().NotNil(condition)
+	suite.Equal(status, condition.Status)
+	suite.Equal(reason, condition.Reason)
+}
+
+func newExtensionTestAccount(extensions ...v1alpha1.Extension) *v1alpha1.Account {
+	return &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-account",
+		},
+		Spec: v1alpha1.AccountSpec{
+			Type:        v1alpha1.AccountTypeAccount,
+			DisplayName: "Test Account",
+			Extensions:  extensions,
+		},
+	}
+}
+
+func newRemovedExtensionStatus(policy v1alpha1.ExtensionDeletionPolicy) v1alpha1.ExtensionStatus {
+	return v1alpha1.ExtensionStatus{
+		TypeMeta:       metav1.TypeMeta{APIVersion: "example.openmfp.org/v1alpha1", Kind: "OtherExtension"},
+		Name:           "removed",
+		Phase:          v1alpha1.ExtensionPhaseRemoved,
+		DeletionPolicy: policy,
+	}
+}
+
+func newTestExtension(spec string, readyConditionType *string) v1alpha1.Extension {
+	return v1alpha1.Extension{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "example.openmfp.org/v1alpha1",
+			Kind:       "ExampleExtension",
+		},
+		SpecGoTemplate:     apiextensionsv1.JSON{Raw: []byte(spec)},
+		ReadyConditionType: readyConditionType,
+	}
+}
+
+func (suite *ExtensionSubroutineTestSuite) mockGetExtensionNotFound() *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+}
+
+func (suite *ExtensionSubroutineTestSuite) mockGetExtension(spec map[string]any, conditions []any) *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual := obj.(*unstructured.Unstructured)
+			actual.SetName(key.Name)
+			actual.Object["spec"] = spec
+			if conditions != nil {
+				actual.Object["status"] = map[string]any{"conditions": conditions}
+			}
+		}).
+		Return(nil)
+}
+
+func (suite *ExtensionSubroutineTestSuite) mockGetAccountInfo() *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual := obj.(*v1alpha1.AccountInfo)
+			actual.Name = key.Name
+			actual.Spec = v1alpha1.AccountInfoSpec{
+				Account:       v1alpha1.AccountLocation{Name: "test-account", Type: v1alpha1.AccountTypeAccount},
+				ParentAccount: &v1alpha1.AccountLocation{Name: "parent-account", Type: v1alpha1.AccountTypeAccount},
+				Organization:  v1alpha1.AccountLocation{Name: "root-org", Type: v1alpha1.AccountTypeOrg},
+				FGA:           v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "store-id"}},
+			}
+		}).
+		Return(nil)
+}
+
+func (suite *ExtensionSubroutineTestSuite) mockPatchExtension(conditions []any) *mocks.Client_Patch_Call {
+	return suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) {
+			if conditions != nil {
+				obj.(*unstructured.Unstructured).Object["status"] = map[string]any{"conditions": conditions}
+			}
+		}).
+		Return(nil)
+}
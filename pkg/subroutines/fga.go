@@ -15,6 +15,7 @@ import (
 	"github.com/platform-mesh/golang-commons/fga/helpers"
 	"github.com/platform-mesh/golang-commons/logger"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,10 +30,11 @@ type FGASubroutine struct {
 	objectType      string
 	parentRelation  string
 	creatorRelation string
+	accountTypes    *v1alpha1.AccountTypeRegistry
 	limiter         workqueue.TypedRateLimiter[ClusteredName]
 }
 
-func NewFGASubroutine(cl client.Client, fgaClient openfgav1.OpenFGAServiceClient, creatorRelation, parentRealtion, objectType string) *FGASubroutine {
+func NewFGASubroutine(cl client.Client, fgaClient openfgav1.OpenFGAServiceClient, creatorRelation, parentRealtion, objectType string, accountTypes *v1alpha1.AccountTypeRegistry) *FGASubroutine {
 	exp := workqueue.NewTypedItemExponentialFailureRateLimiter[ClusteredName](1*time.Second, 120*time.Second)
 	return &FGASubroutine{
 		client:          cl,
@@ -40,6 +42,7 @@ func NewFGASubroutine(cl client.Client, fgaClient openfgav1.OpenFGAServiceClient
 		creatorRelation: creatorRelation,
 		parentRelation:  parentRealtion,
 		objectType:      objectType,
+		accountTypes:    accountTypes,
 		limiter:         exp,
 	}
 }
@@ -58,6 +61,10 @@ func (e *FGASubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObj
 
 	if accountWorkspace.Status.Phase != kcpcorev1alpha.LogicalClusterPhaseReady {
 		log.Info().Msg("workspace is not ready yet, retry")
+		if err := workspaceTimeoutError(ctx, accountWorkspace); err != nil {
+			e.limiter.Forget(cn)
+			return ctrl.Result{}, errors.NewOperatorError(err, false, false)
+		}
 		next := e.limiter.When(cn)
 		return ctrl.Result{RequeueAfter: next}, nil
 	}
@@ -81,7 +88,9 @@ func (e *FGASubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObj
 		return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("account cluster id is empty"), true, true)
 	}
 
-	if account.Spec.Type != v1alpha1.AccountTypeOrg && accountInfo.Spec.ParentAccount.GeneratedClusterId == "" {
+	// the parent of an account with its own store is not part of that store
+	ownsStore := e.accountTypes.OwnsStore(account.Spec.Type)
+	if !ownsStore && accountInfo.Spec.ParentAccount.GeneratedClusterId == "" {
 		log.Error().Msg("parent account cluster id is empty")
 		return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("parent account cluster id is empty"), true, true)
 	}
@@ -89,7 +98,7 @@ func (e *FGASubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObj
 	writes := []*openfgav1.TupleKey{}
 
 	// Parent Name
-	if account.Spec.Type != v1alpha1.AccountTypeOrg {
+	if !ownsStore {
 		parentAccountName := accountInfo.Spec.ParentAccount.Name
 
 		// Determine parent account to create parent relation
@@ -100,26 +109,15 @@ func (e *FGASubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObj
 		})
 	}
 
-	// Assign creator to the account
+	// Assign creator to the account, again after a suspended account was resumed
 	creatorTuplesWritten := meta.IsStatusConditionTrue(account.Status.Conditions, fmt.Sprintf("%s_Ready", e.GetName()))
-	if account.Spec.Creator != nil && !creatorTuplesWritten {
+	accessSuspended := meta.IsStatusConditionTrue(account.Status.Conditions, v1alpha1.ConditionSuspended)
+	if account.Spec.Creator != nil && !account.Spec.Suspended && (!creatorTuplesWritten || accessSuspended) {
 		if valid := validateCreator(*account.Spec.Creator); !valid {
 			log.Error().Err(err).Str("creator", *account.Spec.Creator).Msg("creator string is in the protected service account prefix range")
 			return ctrl.Result{}, errors.NewOperatorError(err, false, false)
 		}
-		creator := formatUser(*account.Spec.Creator)
-
-		writes = append(writes, &openfgav1.TupleKey{
-			Object:   fmt.Sprintf("role:%s/%s/owner", accountInfo.Spec.Account.OriginClusterId, account.Name),
-			Relation: "assignee",
-			User:     fmt.Sprintf("user:%s", creator),
-		})
-
-		writes = append(writes, &openfgav1.TupleKey{
-			Object:   fmt.Sprintf("%s:%s/%s", e.objectType, accountInfo.Spec.Account.OriginClusterId, account.Name),
-			Relation: e.creatorRelation,
-			User:     fmt.Sprintf("role:%s/%s/owner#assignee", accountInfo.Spec.Account.OriginClusterId, account.Name),
-		})
+		writes = append(writes, e.creatorTuples(account, accountInfo)...)
 	}
 
 	for _, writeTuple := range writes {
@@ -141,6 +139,32 @@ func (e *FGASubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObj
 		}
 	}
 
+	if account.Spec.Suspended {
+		if account.Spec.Creator != nil && !accessSuspended {
+			for _, tuple := range e.creatorTuples(account, accountInfo) {
+				_, err = e.fgaClient.Write(ctx, &openfgav1.WriteRequest{
+					StoreId: accountInfo.Spec.FGA.Store.Id,
+					Deletes: &openfgav1.WriteRequestDeletes{
+						TupleKeys: []*openfgav1.TupleKeyWithoutCondition{{Object: tuple.Object, Relation: tuple.Relation, User: tuple.User}},
+					},
+				})
+
+				if helpers.IsDuplicateWriteError(err) {
+					log.Info().Err(err).Msg("Open FGA write failed due to invalid input (possibly trying to deleteTuple nonexisting entry)")
+					err = nil
+				}
+
+				if err != nil {
+					log.Error().Err(err).Msg("Open FGA write failed")
+					return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+				}
+			}
+		}
+		setSuspended(account, true)
+	} else if accessSuspended || account.Status.Phase == v1alpha1.AccountPhaseSuspended {
+		setSuspended(account, false)
+	}
+
 	e.limiter.Forget(cn)
 	return ctrl.Result{}, nil
 }
@@ -149,8 +173,8 @@ func (e *FGASubroutine) Finalize(ctx context.Context, runtimeObj runtimeobject.R
 	account := runtimeObj.(*v1alpha1.Account)
 	log := logger.LoadLoggerFromContext(ctx)
 
-	// Skip fga account finalization for organizations because the store is removed completely
-	if account.Spec.Type != v1alpha1.AccountTypeOrg {
+	// Skip fga account finalization for accounts with their own store because the store is removed completely
+	if !e.accountTypes.OwnsStore(account.Spec.Type) {
 		accountInfo, err := e.getAccountInfo(ctx)
 		if err != nil {
 			log.Error().Err(err).Msg("Couldn't get Store Id")
@@ -162,16 +186,23 @@ func (e *FGASubroutine) Finalize(ctx context.Context, runtimeObj runtimeobject.R
 			return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("FGA Store Id is empty"), true, true)
 		}
 
-		deletes := []*openfgav1.TupleKeyWithoutCondition{}
-		if account.Spec.Type != v1alpha1.AccountTypeOrg {
-			parentAccountName := accountInfo.Spec.Account.Name
-
-			deletes = append(deletes, &openfgav1.TupleKeyWithoutCondition{
-				User:     fmt.Sprintf("%s:%s/%s", e.objectType, accountInfo.Spec.Account.OriginClusterId, parentAccountName),
-				Relation: e.parentRelation,
-				Object:   fmt.Sprintf("%s:%s/%s", e.objectType, accountInfo.Spec.Account.GeneratedClusterId, account.GetName()),
-			})
+		// the parent tuple of a moved account points to the parent recorded in the AccountInfo of the account
+		parent := accountInfo.Spec.Account
+		ownAccountInfo, err := retrieveAccountInfoOf(ctx, e.client, account)
+		if err != nil {
+			log.Error().Err(err).Msg("Couldn't get the AccountInfo of the account")
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 		}
+		if ownAccountInfo != nil && ownAccountInfo.Spec.ParentAccount != nil {
+			parent = *ownAccountInfo.Spec.ParentAccount
+		}
+
+		parentAccountName := parent.Name
+		deletes := []*openfgav1.TupleKeyWithoutCondition{{
+			User:     fmt.Sprintf("%s:%s/%s", e.objectType, parent.OriginClusterId, parentAccountName),
+			Relation: e.parentRelation,
+			Object:   fmt.Sprintf("%s:%s/%s", e.objectType, accountInfo.Spec.Account.GeneratedClusterId, account.GetName()),
+		}}
 
 		if account.Spec.Creator != nil {
 			creator := formatUser(*account.Spec.Creator)
@@ -213,6 +244,41 @@ func (e *FGASubroutine) Finalize(ctx context.Context, runtimeObj runtimeobject.R
 	return ctrl.Result{}, nil
 }
 
+// creatorTuples returns the tuples that make the creator of the account its owner
+func (e *FGASubroutine) creatorTuples(account *v1alpha1.Account, accountInfo *v1alpha1.AccountInfo) []*openfgav1.TupleKey {
+	creator := formatUser(*account.Spec.Creator)
+	return []*openfgav1.TupleKey{
+		{
+			Object:   fmt.Sprintf("role:%s/%s/owner", accountInfo.Spec.Account.OriginClusterId, account.Name),
+			Relation: "assignee",
+			User:     fmt.Sprintf("user:%s", creator),
+		},
+		{
+			Object:   fmt.Sprintf("%s:%s/%s", e.objectType, accountInfo.Spec.Account.OriginClusterId, account.Name),
+			Relation: e.creatorRelation,
+			User:     fmt.Sprintf("role:%s/%s/owner#assignee", accountInfo.Spec.Account.OriginClusterId, account.Name),
+		},
+	}
+}
+
+// setSuspended records whether the creator tuples of the account are removed because the account is suspended
+func setSuspended(account *v1alpha1.Account, suspended bool) {
+	if !suspended {
+		meta.RemoveStatusCondition(&account.Status.Conditions, v1alpha1.ConditionSuspended)
+		if account.Status.Phase == v1alpha1.AccountPhaseSuspended {
+			account.Status.Phase = ""
+		}
+		return
+	}
+	meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ConditionSuspended,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.SuspensionReasonSuspended,
+		Message: "The creator and owner tuples of the account are removed",
+	})
+	account.Status.Phase = v1alpha1.AccountPhaseSuspended
+}
+
 func (e *FGASubroutine) getAccountInfo(ctx context.Context) (*v1alpha1.AccountInfo, error) {
 	// Get AccountInfo For Project
 	accountInfo := &v1alpha1.AccountInfo{}
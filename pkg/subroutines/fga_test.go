@@ -3,6 +3,7 @@ package subroutines_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	kcpcorev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	"k8s.io/apimachinery/pkg/types"
@@ -10,14 +11,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
 	openfgav1 "github.com/openfga/api/proto/openfga/v1"
+	openmfpcontext "github.com/platform-mesh/golang-commons/context"
+	"github.com/platform-mesh/golang-commons/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
 	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/internal/config"
 	"github.com/openmfp/account-operator/pkg/subroutines"
 	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
 )
@@ -40,12 +47,12 @@ func newFgaError(c openfgav1.ErrorCode, m string) *fgaError {
 }
 
 func TestFGASubroutine_GetName(t *testing.T) {
-	routine := subroutines.NewFGASubroutine(nil, nil, "", "", "")
+	routine := subroutines.NewFGASubroutine(nil, nil, "", "", "", nil)
 	assert.Equal(t, "FGASubroutine", routine.GetName())
 }
 
 func TestFGASubroutine_Finalizers(t *testing.T) {
-	routine := subroutines.NewFGASubroutine(nil, nil, "", "", "")
+	routine := subroutines.NewFGASubroutine(nil, nil, "", "", "", nil)
 	assert.Equal(t, []string{"account.core.openmfp.org/fga"}, routine.Finalizers())
 }
 
@@ -513,7 +520,7 @@ func TestFGASubroutine_Process(t *testing.T) {
 				test.setupMocks(openFGAClient, clientMock)
 			}
 
-			routine := subroutines.NewFGASubroutine(clientMock, openFGAClient, "owner", "parent", "account")
+			routine := subroutines.NewFGASubroutine(clientMock, openFGAClient, "owner", "parent", "account", nil)
 
 			if test.expectedPanic {
 				assert.Panics(t, func() {
@@ -636,6 +643,7 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 
 					return nil
 				}).Once()
+				mockGetWorkspaceNotFound(clientMock)
 				openFGAServiceClientMock.EXPECT().
 					Write(mock.Anything, mock.Anything).
 					Return(nil, assert.AnError)
@@ -684,6 +692,7 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 
 					return nil
 				}).Once()
+				mockGetWorkspaceNotFound(clientMock)
 				openFGAServiceClientMock.EXPECT().
 					Write(mock.Anything, mock.Anything).
 					Return(nil, newFgaError(openfgav1.ErrorCode_write_failed_due_to_invalid_input, "error"))
@@ -731,6 +740,7 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 
 					return nil
 				}).Once()
+				mockGetWorkspaceNotFound(clientMock)
 
 				openFGAServiceClientMock.EXPECT().
 					Write(mock.Anything, mock.Anything).
@@ -782,12 +792,45 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 
 					return nil
 				}).Once()
+				mockGetWorkspaceNotFound(clientMock)
 
 				openFGAServiceClientMock.EXPECT().
 					Write(mock.Anything, mock.Anything).
 					Return(&openfgav1.WriteResponse{}, nil).Times(3)
 			},
 		},
+		{
+			name:          "should_delete_the_parent_tuple_of_a_moved_account",
+			expectedError: false,
+			account: &v1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-account",
+				},
+				Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount},
+			},
+			setupMocks: func(openFGAServiceClientMock *mocks.OpenFGAServiceClient, clientMock *mocks.Client) {
+				mockGetWorkspaceByName(clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:old-parent:test-account")
+				// the AccountInfo of the account records the parent it was moved to
+				mockGetAccountInfos(clientMock, map[string]v1alpha1.AccountInfoSpec{
+					"abcdefghi": {
+						Account: v1alpha1.AccountLocation{Name: "old-parent", GeneratedClusterId: "abcdefghi", OriginClusterId: "old-parent-origin"},
+						FGA:     v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "123123"}},
+					},
+					"some-cluster-id-test-account": {
+						ParentAccount: &v1alpha1.AccountLocation{Name: "new-parent", GeneratedClusterId: "new-parent-ws", OriginClusterId: "new-parent-origin"},
+					},
+				})
+
+				openFGAServiceClientMock.EXPECT().
+					Write(mock.Anything, mock.Anything).
+					Run(func(_ context.Context, in *openfgav1.WriteRequest, _ ...grpc.CallOption) {
+						assert.Equal(t, "123123", in.StoreId)
+						assert.Equal(t, "account:new-parent-origin/new-parent", in.Deletes.TupleKeys[0].User)
+						assert.Equal(t, "account:abcdefghi/test-account", in.Deletes.TupleKeys[0].Object)
+					}).
+					Return(&openfgav1.WriteResponse{}, nil).Once()
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -800,7 +843,7 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 				test.setupMocks(openFGAClient, k8sClient)
 			}
 
-			routine := subroutines.NewFGASubroutine(k8sClient, openFGAClient, "owner", "parent", "account")
+			routine := subroutines.NewFGASubroutine(k8sClient, openFGAClient, "owner", "parent", "account", nil)
 			ctx := kontext.WithCluster(context.Background(), "abcdefghi")
 			_, err := routine.Finalize(ctx, test.account)
 			if test.expectedError {
@@ -812,3 +855,111 @@ func TestCreatorSubroutine_Finalize(t *testing.T) {
 		})
 	}
 }
+
+func TestFGASubroutine_Process_Provisioning_Timeout(t *testing.T) {
+	log, err := logger.New(logger.DefaultConfig())
+	require.NoError(t, err)
+	cfg := config.OperatorConfig{}
+	cfg.Subroutines.Workspace.ProvisioningTimeout = 10 * time.Minute
+	ctx, _, _ := openmfpcontext.StartContext(log, cfg, 1*time.Minute)
+
+	k8sClient := mocks.NewClient(t)
+	mockGetWorkspaceCreatedBefore(k8sClient, time.Hour)
+	routine := subroutines.NewFGASubroutine(k8sClient, mocks.NewOpenFGAServiceClient(t), "owner", "parent", "account", nil)
+
+	account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "test-account"}, Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount}}
+	res, opErr := routine.Process(kontext.WithCluster(ctx, "abcdefghi"), account)
+
+	require.NotNil(t, opErr)
+	assert.False(t, opErr.Retry())
+	assert.Zero(t, res.RequeueAfter)
+}
+
+func TestFGASubroutine_Process_Suspension(t *testing.T) {
+	accountInfo := v1alpha1.AccountInfo{
+		Spec: v1alpha1.AccountInfoSpec{
+			Account:       v1alpha1.AccountLocation{Name: "test-account", GeneratedClusterId: "test-account-id", OriginClusterId: "org-id"},
+			ParentAccount: &v1alpha1.AccountLocation{Name: "root-org", GeneratedClusterId: "org-id", OriginClusterId: "root-id"},
+			FGA:           v1alpha1.FGAInfo{Store: v1alpha1.StoreInfo{Id: "123123"}},
+		},
+	}
+	ready := metav1.Condition{Type: "FGASubroutine_Ready", Status: metav1.ConditionTrue}
+	suspended := metav1.Condition{Type: v1alpha1.ConditionSuspended, Status: metav1.ConditionTrue, Reason: v1alpha1.SuspensionReasonSuspended}
+
+	testCases := []struct {
+		name            string
+		suspend         bool
+		conditions      []metav1.Condition
+		expectedWrites  []string
+		expectedDeletes []string
+		expectedPhase   v1alpha1.AccountPhase
+	}{
+		{
+			name:            "suspending removes the creator tuples",
+			suspend:         true,
+			conditions:      []metav1.Condition{ready},
+			expectedWrites:  []string{"account:org-id/test-account#parent@account:root-id/root-org"},
+			expectedDeletes: []string{"role:org-id/test-account/owner#assignee@user:test-creator", "account:org-id/test-account#owner@role:org-id/test-account/owner#assignee"},
+			expectedPhase:   v1alpha1.AccountPhaseSuspended,
+		},
+		{
+			name:           "suspended accounts are not revoked twice",
+			suspend:        true,
+			conditions:     []metav1.Condition{ready, suspended},
+			expectedWrites: []string{"account:org-id/test-account#parent@account:root-id/root-org"},
+			expectedPhase:  v1alpha1.AccountPhaseSuspended,
+		},
+		{
+			name:       "resuming writes the creator tuples again",
+			conditions: []metav1.Condition{ready, suspended},
+			expectedWrites: []string{
+				"account:org-id/test-account#parent@account:root-id/root-org",
+				"role:org-id/test-account/owner#assignee@user:test-creator",
+				"account:org-id/test-account#owner@role:org-id/test-account/owner#assignee",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			account := &v1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-account"},
+				Spec:       v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount, Creator: ptr.To("test-creator"), Suspended: test.suspend},
+				Status:     v1alpha1.AccountStatus{Conditions: append([]metav1.Condition{}, test.conditions...)},
+			}
+			if meta.IsStatusConditionTrue(test.conditions, v1alpha1.ConditionSuspended) {
+				account.Status.Phase = v1alpha1.AccountPhaseSuspended
+			}
+
+			clientMock := new(mocks.Client)
+			mockGetWorkspaceByName(clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:openmfp:orgs:root-org").Once()
+			clientMock.EXPECT().Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+				Run(func(_ context.Context, _ types.NamespacedName, o client.Object, _ ...client.GetOption) {
+					accountInfo.DeepCopyInto(o.(*v1alpha1.AccountInfo))
+				}).
+				Return(nil)
+
+			var writes, deletes []string
+			fgaMock := new(mocks.OpenFGAServiceClient)
+			fgaMock.EXPECT().Write(mock.Anything, mock.Anything).
+				Run(func(_ context.Context, req *openfgav1.WriteRequest, _ ...grpc.CallOption) {
+					for _, tuple := range req.GetWrites().GetTupleKeys() {
+						writes = append(writes, tuple.Object+"#"+tuple.Relation+"@"+tuple.User)
+					}
+					for _, tuple := range req.GetDeletes().GetTupleKeys() {
+						deletes = append(deletes, tuple.Object+"#"+tuple.Relation+"@"+tuple.User)
+					}
+				}).
+				Return(&openfgav1.WriteResponse{}, nil)
+
+			routine := subroutines.NewFGASubroutine(clientMock, fgaMock, "owner", "parent", "account", nil)
+			_, err := routine.Process(kontext.WithCluster(context.Background(), "some-cluster"), account)
+
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedWrites, writes)
+			assert.Equal(t, test.expectedDeletes, deletes)
+			assert.Equal(t, test.expectedPhase, account.Status.Phase)
+			assert.Equal(t, test.suspend, meta.IsStatusConditionTrue(account.Status.Conditions, v1alpha1.ConditionSuspended))
+		})
+	}
+}
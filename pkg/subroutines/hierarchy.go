@@ -0,0 +1,98 @@
+package subroutines
+
+import (
+	"context"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+var _ subroutine.Subroutine = (*HierarchySubroutine)(nil)
+
+const HierarchySubroutineName = "HierarchySubroutine"
+
+// HierarchySubroutine checks the depth of an account against the limit of its organization and reports the result in
+// the HierarchyDepthValid condition. A too deep account does not block the reconciliation, the webhook rejects new ones,
+// this catches accounts that were created before the limit was lowered or that were moved below a deeper parent.
+type HierarchySubroutine struct {
+	client       client.Client
+	limits       v1alpha1.DepthLimits
+	accountTypes *v1alpha1.AccountTypeRegistry
+}
+
+func NewHierarchySubroutine(cl client.Client, limits v1alpha1.DepthLimits, accountTypes *v1alpha1.AccountTypeRegistry) *HierarchySubroutine {
+	return &HierarchySubroutine{client: cl, limits: limits, accountTypes: accountTypes}
+}
+
+func (r *HierarchySubroutine) GetName() string {
+	return HierarchySubroutineName
+}
+
+func (r *HierarchySubroutine) Finalizers() []string { // coverage-ignore
+	return []string{}
+}
+
+func (r *HierarchySubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (r *HierarchySubroutine) Process(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+	log := logger.LoadLoggerFromContext(ctx)
+
+	if r.accountTypes.IsOrganization(instance.Spec.Type) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid)
+		return ctrl.Result{}, nil
+	}
+
+	// the parent of a moved account is recorded in its AccountInfo
+	accountInfo, err := retrieveAccountInfoOf(ctx, r.client, instance)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	parent := &v1alpha1.AccountInfo{}
+	if err := r.client.Get(parentContext(ctx, accountInfo), client.ObjectKey{Name: DefaultAccountInfoName}, parent); err != nil {
+		if kerrors.IsNotFound(err) {
+			// the placement of the account is reported by the WorkspaceSubroutine
+			meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	if r.limits.For(parent.Spec.Organization.Name) == 0 {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid)
+		return ctrl.Result{}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionHierarchyDepthValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.HierarchyReasonDepthWithinLimit,
+		Message:            "The account is nested within the maximum depth of its organization",
+		ObservedGeneration: instance.GetGeneration(),
+	}
+	err = v1alpha1.ValidateDepth(ctx, r.client, r.limits, parent)
+	var depthErr *v1alpha1.DepthExceededError
+	switch {
+	case errors.As(err, &depthErr):
+		log.Info().Err(err).Msg("account exceeds the maximum hierarchy depth")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.HierarchyReasonDepthExceeded
+		condition.Message = depthErr.Error()
+	case err != nil:
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,178 @@
+package subroutines_test
+
+import (
+	"context"
+	"testing"
+
+	kcpcorev1alpha1 "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
+)
+
+type HierarchySubroutineTestSuite struct {
+	suite.Suite
+
+	// Mocks
+	clientMock *mocks.Client
+
+	log *logger.Logger
+}
+
+func (suite *HierarchySubroutineTestSuite) SetupTest() {
+	// Setup Mocks
+	suite.clientMock = new(mocks.Client)
+
+	var err error
+	suite.log, err = logger.New(logger.DefaultConfig())
+	suite.Require().NoError(err)
+}
+
+func TestHierarchySubroutineTestSuite(t *testing.T) {
+	suite.Run(t, new(HierarchySubroutineTestSuite))
+}
+
+func (suite *HierarchySubroutineTestSuite) TestGetName_OK() {
+	testObj := subroutines.NewHierarchySubroutine(suite.clientMock, v1alpha1.DepthLimits{}, nil)
+	suite.Equal(subroutines.HierarchySubroutineName, testObj.GetName())
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Within_Limit() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	suite.mockGetAccountInfos(nil)
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 2}, "team-ws", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.verifyCondition(testAccount, metav1.ConditionTrue, v1alpha1.HierarchyReasonDepthWithinLimit)
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Exceeds_Limit() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	suite.mockGetAccountInfos(nil)
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1}, "team-ws", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.verifyCondition(testAccount, metav1.ConditionFalse, v1alpha1.HierarchyReasonDepthExceeded)
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Moved_Account() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	// the AccountInfo of the account records the parent it was moved to
+	suite.mockGetAccountInfos(&v1alpha1.AccountLocation{Name: "team", GeneratedClusterId: "team-ws", Type: v1alpha1.AccountTypeAccount})
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1}, "org-ws", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.verifyCondition(testAccount, metav1.ConditionFalse, v1alpha1.HierarchyReasonDepthExceeded)
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Organization_Without_Limit() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	suite.mockGetAccountInfos(nil)
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1, Organizations: map[string]int{"org": 0}}, "team-ws", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Nil(meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid))
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Organization_Account() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeOrg)
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1}, "root", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Nil(meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid))
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Parent_Not_Found() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	suite.mockGetAccountInfos(nil)
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1}, "unknown-ws", testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Nil(meta.FindStatusCondition(testAccount.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid))
+}
+
+func (suite *HierarchySubroutineTestSuite) TestProcessing_Parent_Error() {
+	// Given
+	testAccount := newMovedAccount(v1alpha1.AccountTypeAccount)
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Return(kerrors.NewInternalError(context.DeadlineExceeded))
+
+	// When
+	err := suite.process(v1alpha1.DepthLimits{Default: 1}, "team-ws", testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+}
+
+// process runs the subroutine with the limits in the cluster, the account starts with a valid depth condition
+func (suite *HierarchySubroutineTestSuite) process(limits v1alpha1.DepthLimits, cluster string, account *v1alpha1.Account) errors.OperatorError {
+	meta.SetStatusCondition(&account.Status.Conditions, metav1.Condition{
+		Type: v1alpha1.ConditionHierarchyDepthValid, Status: metav1.ConditionTrue, Reason: v1alpha1.HierarchyReasonDepthWithinLimit,
+	})
+	ctx := logger.SetLoggerInContext(kontext.WithCluster(context.Background(), logicalcluster.Name(cluster)), suite.log)
+
+	testObj := subroutines.NewHierarchySubroutine(suite.clientMock, limits, nil)
+	_, err := testObj.Process(ctx, account)
+	suite.clientMock.AssertExpectations(suite.T())
+	return err
+}
+
+func (suite *HierarchySubroutineTestSuite) verifyCondition(account *v1alpha1.Account, status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(account.Status.Conditions, v1alpha1.ConditionHierarchyDepthValid)
+	suite.Require().NotNil(condition)
+	suite.Equal(status, condition.Status)
+	suite.Equal(reason, condition.Reason)
+}
+
+// mockGetAccountInfos mocks the workspace of the account and the AccountInfos of the hierarchy, the AccountInfo of the
+// account exists if its parent is given
+func (suite *HierarchySubroutineTestSuite) mockGetAccountInfos(parent *v1alpha1.AccountLocation) *mocks.Client_Get_Call {
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "root:orgs:org:team")
+	org := v1alpha1.AccountLocation{Name: "org", GeneratedClusterId: "org-ws", Type: v1alpha1.AccountTypeOrg}
+	team := v1alpha1.AccountLocation{Name: "team", GeneratedClusterId: "team-ws", Type: v1alpha1.AccountTypeAccount}
+	specs := map[string]v1alpha1.AccountInfoSpec{
+		"org-ws":  {Account: org, Organization: org},
+		"team-ws": {Account: team, ParentAccount: &org, Organization: org},
+	}
+	if parent != nil {
+		specs["some-cluster-id-team"] = v1alpha1.AccountInfoSpec{ParentAccount: parent, Organization: org}
+	}
+	return mockGetAccountInfos(suite.clientMock, specs)
+}
@@ -0,0 +1,275 @@
+package subroutines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+)
+
+var _ subroutine.Subroutine = (*TrashSubroutine)(nil)
+
+const (
+	TrashSubroutineName      = "TrashSubroutine"
+	TrashSubroutineFinalizer = "account.core.openmfp.org/trash"
+
+	// restoreRetryInterval is the time after which the creation of a restored account is retried while the deleted
+	// account still holds its name
+	restoreRetryInterval = time.Second
+)
+
+// TrashSubroutine keeps deleted accounts in the Deleted phase for a retention period. The FGA finalizer runs right
+// away and revokes the access to the account, the workspace and the AccountInfo wait for the trash finalizer. Setting
+// the restore annotation during the retention period brings the account back.
+type TrashSubroutine struct {
+	client    client.Client
+	retention time.Duration
+}
+
+func NewTrashSubroutine(cl client.Client, retention time.Duration) *TrashSubroutine {
+	return &TrashSubroutine{client: cl, retention: retention}
+}
+
+func (r *TrashSubroutine) GetName() string {
+	return TrashSubroutineName
+}
+
+func (r *TrashSubroutine) Finalizers() []string { // coverage-ignore
+	return []string{TrashSubroutineFinalizer}
+}
+
+func (r *TrashSubroutine) Process(_ context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+
+	// a restored account may have received the status of the deleted account it replaces
+	if instance.Status.Phase == v1alpha1.AccountPhaseDeleted {
+		instance.Status.Phase = ""
+	}
+	instance.Status.RestorableUntil = nil
+	return ctrl.Result{}, nil
+}
+
+func (r *TrashSubroutine) Finalize(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	instance := ro.(*v1alpha1.Account)
+
+	if instance.GetAnnotations()[v1alpha1.RestoreAnnotation] == "true" {
+		return r.restore(ctx, instance)
+	}
+
+	restorableUntil := instance.GetDeletionTimestamp().Add(r.retention)
+	remaining := time.Until(restorableUntil)
+	if remaining <= 0 {
+		return ctrl.Result{}, nil
+	}
+
+	instance.Status.Phase = v1alpha1.AccountPhaseDeleted
+	instance.Status.RestorableUntil = &metav1.Time{Time: restorableUntil}
+	return ctrl.Result{RequeueAfter: remaining}, nil
+}
+
+// restore replaces the deleted account with a copy of itself. An object that is being deleted can not be brought back
+// and keeps its name until its finalizers are gone. The copy must pass admission, then it is recorded on the workspace
+// together with the adoption annotation, and only then the deleted account is released without running the remaining
+// finalizers. The copy is created once the name is free again, or by the sweeper if the restore is interrupted.
+func (r *TrashSubroutine) restore(ctx context.Context, instance *v1alpha1.Account) (ctrl.Result, errors.OperatorError) {
+	log := logger.LoadLoggerFromContext(ctx)
+
+	replacement := restoredAccount(instance)
+	if len(instance.GetFinalizers()) > 0 {
+		// the name is still taken by the deleted account, a dry run that fails only because of that passed admission
+		err := r.client.Create(ctx, replacement.DeepCopy(), client.DryRunAll)
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("restoring account %q: %w", instance.Name, err), true, true)
+		}
+
+		err = r.detachWorkspace(ctx, instance, replacement)
+		if kerrors.IsNotFound(err) {
+			// without the workspace there is nothing to restore and nowhere to record the replacement
+			return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("restoring account %q: the workspace of the account is gone", instance.Name), false, false)
+		}
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("restoring account %q: %w", instance.Name, err), true, true)
+		}
+
+		original := instance.DeepCopy()
+		instance.SetFinalizers(nil)
+		instance.Status.Phase = ""
+		instance.Status.RestorableUntil = nil
+		if err := r.client.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+	}
+
+	err := r.client.Create(ctx, replacement)
+	if kerrors.IsAlreadyExists(err) {
+		// the deleted account is not gone yet
+		return ctrl.Result{RequeueAfter: restoreRetryInterval}, nil
+	}
+	if err != nil {
+		// the replacement is recorded on the workspace, the sweeper creates it
+		return ctrl.Result{}, errors.NewOperatorError(fmt.Errorf("restoring account %q: %w", instance.Name, err), true, true)
+	}
+
+	log.Info().Str("account", instance.Name).Msg("restored deleted account")
+	return ctrl.Result{}, nil
+}
+
+// restoredAccount returns the copy of a deleted account that replaces it
+func restoredAccount(instance *v1alpha1.Account) *v1alpha1.Account {
+	annotations := make(map[string]string, len(instance.GetAnnotations()))
+	for key, value := range instance.GetAnnotations() {
+		if key != v1alpha1.RestoreAnnotation && key != logicalcluster.AnnotationKey {
+			annotations[key] = value
+		}
+	}
+	return &v1alpha1.Account{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "Account"},
+		ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Labels: instance.GetLabels(), Annotations: annotations},
+		Spec:       *instance.Spec.DeepCopy(),
+	}
+}
+
+// detachWorkspace removes the deleted account from the owners of its workspace, annotates the workspace for adoption by
+// the account with the same name and records the replacement on it
+func (r *TrashSubroutine) detachWorkspace(ctx context.Context, instance *v1alpha1.Account, replacement *v1alpha1.Account) error {
+	ws := &kcptenancyv1alpha.Workspace{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: instance.Name}, ws); err != nil {
+		return err
+	}
+
+	recorded, err := json.Marshal(replacement)
+	if err != nil {
+		return err
+	}
+
+	original := ws.DeepCopy()
+	ws.SetOwnerReferences(slices.DeleteFunc(ws.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == instance.UID
+	}))
+	if ws.Annotations == nil {
+		ws.Annotations = map[string]string{}
+	}
+	ws.Annotations[v1alpha1.AdoptAnnotation] = instance.Name
+	ws.Annotations[v1alpha1.RestoredAccountAnnotation] = string(recorded)
+	return r.client.Patch(ctx, ws, client.MergeFrom(original))
+}
+
+var _ manager.LeaderElectionRunnable = (*TrashSweeper)(nil)
+
+// TrashSweeper periodically finalizes the deleted accounts whose retention period expired and creates the accounts
+// recorded on workspaces by an interrupted restore. The accounts and workspaces are listed from the cache of all
+// workspaces, so neither depends on a pending requeue.
+type TrashSweeper struct {
+	client   client.Client
+	interval time.Duration
+	log      *logger.Logger
+}
+
+func NewTrashSweeper(cl client.Client, interval time.Duration, log *logger.Logger) *TrashSweeper {
+	return &TrashSweeper{client: cl, interval: interval, log: log}
+}
+
+func (s *TrashSweeper) NeedLeaderElection() bool { // coverage-ignore
+	return true
+}
+
+func (s *TrashSweeper) Start(ctx context.Context) error { // coverage-ignore
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.Sweep(ctx, time.Now()); err != nil {
+			s.log.Error().Err(err).Msg("failed to sweep deleted accounts")
+		}
+	}, s.interval)
+	return nil
+}
+
+// Sweep removes the trash finalizer of all accounts that were restorable until before now and creates the restored
+// accounts that do not exist yet.
+func (s *TrashSweeper) Sweep(ctx context.Context, now time.Time) error {
+	accounts := &v1alpha1.AccountList{}
+	if err := s.client.List(ctx, accounts); err != nil {
+		return err
+	}
+
+	errs := s.createRestoredAccounts(ctx)
+	for i := range accounts.Items {
+		account := &accounts.Items[i]
+		if !trashExpired(account, now) {
+			continue
+		}
+
+		clusterCtx := kontext.WithCluster(ctx, logicalcluster.From(account))
+		original := account.DeepCopy()
+		controllerutil.RemoveFinalizer(account, TrashSubroutineFinalizer)
+		if err := s.client.Patch(clusterCtx, account, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("finalizing deleted account %q: %w", account.Name, err))
+			continue
+		}
+		s.log.Info().Str("account", account.Name).Str("cluster", logicalcluster.From(account).String()).Msg("retention period of deleted account expired")
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// createRestoredAccounts creates the accounts recorded on workspaces, as long as the deleted account or the restored
+// account holds the name the creation fails and nothing is done.
+func (s *TrashSweeper) createRestoredAccounts(ctx context.Context) []error {
+	workspaces := &kcptenancyv1alpha.WorkspaceList{}
+	if err := s.client.List(ctx, workspaces); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		recorded, ok := ws.GetAnnotations()[v1alpha1.RestoredAccountAnnotation]
+		if !ok || ws.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		account := &v1alpha1.Account{}
+		if err := json.Unmarshal([]byte(recorded), account); err != nil {
+			errs = append(errs, fmt.Errorf("decoding restored account of workspace %q: %w", ws.Name, err))
+			continue
+		}
+		err := s.client.Create(kontext.WithCluster(ctx, logicalcluster.From(ws)), account)
+		if kerrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("creating restored account %q: %w", account.Name, err))
+			continue
+		}
+		s.log.Info().Str("account", account.Name).Str("cluster", logicalcluster.From(ws).String()).Msg("created restored account")
+	}
+	return errs
+}
+
+func trashExpired(account *v1alpha1.Account, now time.Time) bool {
+	return inTrash(account) && !now.Before(account.Status.RestorableUntil.Time)
+}
+
+// inTrash reports whether the account waits in the Deleted phase for its retention period and is not being restored
+func inTrash(account *v1alpha1.Account) bool {
+	return account.GetDeletionTimestamp() != nil &&
+		controllerutil.ContainsFinalizer(account, TrashSubroutineFinalizer) &&
+		account.GetAnnotations()[v1alpha1.RestoreAnnotation] != "true" &&
+		account.Status.RestorableUntil != nil
+}
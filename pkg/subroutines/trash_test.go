@@ -0,0 +1,381 @@
+package subroutines_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/account-operator/api/v1alpha1"
+	"github.com/openmfp/account-operator/pkg/subroutines"
+	"github.com/openmfp/account-operator/pkg/subroutines/mocks"
+)
+
+type TrashSubroutineTestSuite struct {
+	suite.Suite
+
+	// Tested Object(s)
+	testObj *subroutines.TrashSubroutine
+	sweeper *subroutines.TrashSweeper
+
+	// Mocks
+	clientMock *mocks.Client
+
+	context context.Context
+	log     *logger.Logger
+}
+
+func (suite *TrashSubroutineTestSuite) SetupTest() {
+	// Setup Mocks
+	suite.clientMock = new(mocks.Client)
+
+	var err error
+	suite.log, err = logger.New(logger.DefaultConfig())
+	suite.Require().NoError(err)
+	suite.context = logger.SetLoggerInContext(kontext.WithCluster(context.Background(), "org-ws"), suite.log)
+
+	// Initialize Tested Object(s)
+	suite.testObj = subroutines.NewTrashSubroutine(suite.clientMock, 24*time.Hour)
+	suite.sweeper = subroutines.NewTrashSweeper(suite.clientMock, time.Minute, suite.log)
+}
+
+func TestTrashSubroutineTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashSubroutineTestSuite))
+}
+
+func (suite *TrashSubroutineTestSuite) TestGetName_OK() {
+	suite.Equal(subroutines.TrashSubroutineName, suite.testObj.GetName())
+}
+
+func (suite *TrashSubroutineTestSuite) TestProcessing_Clears_Deleted_Status() {
+	// Given
+	testAccount := newDeletedAccount(time.Now())
+	testAccount.DeletionTimestamp = nil
+	testAccount.Status.Phase = v1alpha1.AccountPhaseDeleted
+	testAccount.Status.RestorableUntil = &metav1.Time{Time: time.Now()}
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Empty(testAccount.Status.Phase)
+	suite.Nil(testAccount.Status.RestorableUntil)
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Keeps_Account_During_Retention() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.InDelta(23*time.Hour, res.RequeueAfter, float64(time.Minute))
+	suite.Equal(v1alpha1.AccountPhaseDeleted, testAccount.Status.Phase)
+	suite.Require().NotNil(testAccount.Status.RestorableUntil)
+	suite.Equal(testAccount.DeletionTimestamp.Add(24*time.Hour), testAccount.Status.RestorableUntil.Time)
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Retention_Expired() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-25 * time.Hour))
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restores_Account() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+	testAccount.Status.Phase = v1alpha1.AccountPhaseDeleted
+
+	// the name is taken until the deleted account is gone
+	dryRun := suite.mockCreateReplacement(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "accounts"}, "team"), []client.CreateOption{client.DryRunAll})
+	suite.mockGetOwnedWorkspace()
+	var recorded *v1alpha1.Account
+	detach := suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace"), mock.Anything).
+		Run(func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) {
+			suite.Equal([]metav1.OwnerReference{{Name: "other", UID: "other-uid"}}, obj.GetOwnerReferences())
+			suite.Equal("team", obj.GetAnnotations()[v1alpha1.AdoptAnnotation])
+			recorded = &v1alpha1.Account{}
+			suite.Require().NoError(json.Unmarshal([]byte(obj.GetAnnotations()[v1alpha1.RestoredAccountAnnotation]), recorded))
+		}).
+		Return(nil).
+		NotBefore(dryRun.Call)
+	release := suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.Account"), mock.Anything).
+		Run(func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) {
+			suite.Empty(obj.GetFinalizers())
+		}).
+		Return(nil).
+		NotBefore(detach)
+	var replacement *v1alpha1.Account
+	suite.mockCreateReplacement(nil).
+		Run(func(_ context.Context, obj client.Object, _ ...client.CreateOption) {
+			replacement = obj.(*v1alpha1.Account)
+		}).
+		NotBefore(release)
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Empty(testAccount.Status.Phase)
+	suite.Require().NotNil(replacement)
+	suite.Equal("team", replacement.Name)
+	suite.Empty(replacement.UID)
+	suite.Equal(map[string]string{"team": "a"}, replacement.Labels)
+	suite.Equal(map[string]string{"owner": "team-a"}, replacement.Annotations)
+	suite.Equal(testAccount.Spec, replacement.Spec)
+	suite.Equal("creator", *replacement.Spec.Creator)
+	suite.Equal(replacement, recorded)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restore_Not_Admitted() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+
+	suite.mockCreateReplacement(kerrors.NewForbidden(schema.GroupResource{Resource: "accounts"}, "team", assert.AnError), []client.CreateOption{client.DryRunAll})
+
+	// When
+	_, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	// the deleted account and its workspace are kept until the replacement is admitted
+	suite.Equal([]string{subroutines.TrashSubroutineFinalizer, subroutines.WorkspaceSubroutineFinalizer}, testAccount.GetFinalizers())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restore_Create_Failed() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+	testAccount.Finalizers = nil
+
+	suite.mockCreateReplacement(kerrors.NewInternalError(context.DeadlineExceeded))
+
+	// When
+	_, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restore_Waits_For_Name() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+	testAccount.Finalizers = nil
+
+	suite.mockCreateReplacement(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "accounts"}, "team"))
+
+	// When
+	res, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.NotZero(res.RequeueAfter)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restore_Workspace_Gone() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+
+	suite.mockCreateReplacement(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "accounts"}, "team"), []client.CreateOption{client.DryRunAll})
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, client.ObjectKey{Name: "team"}, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{Resource: "workspaces"}, "team"))
+
+	// When
+	_, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.Equal([]string{subroutines.TrashSubroutineFinalizer, subroutines.WorkspaceSubroutineFinalizer}, testAccount.GetFinalizers())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestFinalize_Restore_Workspace_Error() {
+	// Given
+	testAccount := newDeletedAccount(time.Now().Add(-time.Hour))
+	testAccount.Annotations[v1alpha1.RestoreAnnotation] = "true"
+
+	suite.mockCreateReplacement(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "accounts"}, "team"), []client.CreateOption{client.DryRunAll})
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewInternalError(context.DeadlineExceeded))
+
+	// When
+	_, err := suite.testObj.Finalize(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.Equal([]string{subroutines.TrashSubroutineFinalizer, subroutines.WorkspaceSubroutineFinalizer}, testAccount.GetFinalizers())
+}
+
+func (suite *TrashSubroutineTestSuite) TestSweep_Finalizes_Expired_Accounts() {
+	// Given
+	now := time.Now()
+	expired := newDeletedAccount(now.Add(-2 * time.Hour))
+	expired.Status.RestorableUntil = &metav1.Time{Time: now.Add(-time.Hour)}
+	retained := newDeletedAccount(now.Add(-2 * time.Hour))
+	retained.Name = "retained"
+	retained.Status.RestorableUntil = &metav1.Time{Time: now.Add(time.Hour)}
+	restoring := newDeletedAccount(now.Add(-2 * time.Hour))
+	restoring.Name = "restoring"
+	restoring.Annotations[v1alpha1.RestoreAnnotation] = "true"
+	restoring.Status.RestorableUntil = &metav1.Time{Time: now.Add(-time.Hour)}
+	active := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "active"}}
+
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Run(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+			list.(*v1alpha1.AccountList).Items = []v1alpha1.Account{*expired, *retained, *restoring, *active}
+		}).
+		Return(nil)
+	suite.mockListWorkspaces()
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.Account"), mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			suite.Equal("org-ws", cluster.String())
+			suite.Equal("team", obj.GetName())
+			suite.Equal([]string{subroutines.WorkspaceSubroutineFinalizer}, obj.GetFinalizers())
+		}).
+		Return(nil).Once()
+
+	// When
+	err := suite.sweeper.Sweep(context.Background(), now)
+
+	// Then
+	suite.NoError(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestSweep_Creates_Restored_Accounts() {
+	// Given
+	restored, err := json.Marshal(&v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec:       v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount, Creator: ptr.To("creator")},
+	})
+	suite.Require().NoError(err)
+	adopted := kcptenancyv1alpha.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "adopted"}}
+	pending := kcptenancyv1alpha.Workspace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team",
+		Annotations: map[string]string{logicalcluster.AnnotationKey: "org-ws", v1alpha1.RestoredAccountAnnotation: string(restored)},
+	}}
+	existing := *pending.DeepCopy()
+	existing.Name = "existing"
+
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Return(nil)
+	suite.mockListWorkspaces(adopted, pending, existing)
+	suite.clientMock.EXPECT().
+		Create(mock.Anything, mock.AnythingOfType("*v1alpha1.Account")).
+		Run(func(ctx context.Context, obj client.Object, _ ...client.CreateOption) {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			suite.Equal("org-ws", cluster.String())
+			suite.Equal("team", obj.GetName())
+			suite.Equal("creator", *obj.(*v1alpha1.Account).Spec.Creator)
+		}).
+		Return(nil).Once()
+	// the deleted account or the restored account still holds the name
+	suite.clientMock.EXPECT().
+		Create(mock.Anything, mock.AnythingOfType("*v1alpha1.Account")).
+		Return(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "accounts"}, "team")).Once()
+
+	// When
+	err = suite.sweeper.Sweep(context.Background(), time.Now())
+
+	// Then
+	suite.NoError(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *TrashSubroutineTestSuite) TestSweep_List_Error() {
+	// Given
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Return(kerrors.NewInternalError(context.DeadlineExceeded))
+
+	// When
+	err := suite.sweeper.Sweep(context.Background(), time.Now())
+
+	// Then
+	suite.Error(err)
+}
+
+func (suite *TrashSubroutineTestSuite) mockCreateReplacement(err error, opts ...any) *mocks.Client_Create_Call {
+	call := suite.clientMock.EXPECT().
+		Create(mock.Anything, mock.AnythingOfType("*v1alpha1.Account"), opts...).
+		Return(err)
+	call.Once()
+	return call
+}
+
+func (suite *TrashSubroutineTestSuite) mockListWorkspaces(workspaces ...kcptenancyv1alpha.Workspace) *mocks.Client_List_Call {
+	return suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.WorkspaceList")).
+		Run(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) {
+			list.(*kcptenancyv1alpha.WorkspaceList).Items = workspaces
+		}).
+		Return(nil)
+}
+
+func (suite *TrashSubroutineTestSuite) mockGetOwnedWorkspace() *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, client.ObjectKey{Name: "team"}, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Run(func(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) {
+			obj.SetName(key.Name)
+			obj.SetOwnerReferences([]metav1.OwnerReference{{Name: "team", UID: "team-uid"}, {Name: "other", UID: "other-uid"}})
+		}).
+		Return(nil)
+}
+
+func newDeletedAccount(deletedAt time.Time) *v1alpha1.Account {
+	return &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "team",
+			UID:               "team-uid",
+			Labels:            map[string]string{"team": "a"},
+			Annotations:       map[string]string{logicalcluster.AnnotationKey: "org-ws", "owner": "team-a"},
+			Finalizers:        []string{subroutines.TrashSubroutineFinalizer, subroutines.WorkspaceSubroutineFinalizer},
+			DeletionTimestamp: &metav1.Time{Time: deletedAt},
+		},
+		Spec: v1alpha1.AccountSpec{Type: v1alpha1.AccountTypeAccount, DisplayName: "Team", Creator: ptr.To("creator")},
+	}
+}
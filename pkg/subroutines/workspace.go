@@ -2,18 +2,25 @@ package subroutines
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	kcpcorev1alpha "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	kcptenancyv1alpha "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/logicalcluster/v3"
 	commonconfig "github.com/platform-mesh/golang-commons/config"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
 	"github.com/platform-mesh/golang-commons/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
 	corev1alpha1 "github.com/openmfp/account-operator/api/v1alpha1"
 	"github.com/openmfp/account-operator/internal/config"
@@ -22,16 +29,20 @@ import (
 const (
 	WorkspaceSubroutineName      = "WorkspaceSubroutine"
 	WorkspaceSubroutineFinalizer = "account.core.openmfp.org/finalizer"
+
+	// maxReportedChildAccounts limits the number of child accounts listed in the ChildAccountsDeleted condition
+	maxReportedChildAccounts = 10
 )
 
 type WorkspaceSubroutine struct {
-	client  client.Client
-	limiter workqueue.TypedRateLimiter[ClusteredName]
+	client       client.Client
+	accountTypes *corev1alpha1.AccountTypeRegistry
+	limiter      workqueue.TypedRateLimiter[ClusteredName]
 }
 
-func NewWorkspaceSubroutine(client client.Client) *WorkspaceSubroutine {
+func NewWorkspaceSubroutine(client client.Client, accountTypes *corev1alpha1.AccountTypeRegistry) *WorkspaceSubroutine {
 	exp := workqueue.NewTypedItemExponentialFailureRateLimiter[ClusteredName](1*time.Second, 120*time.Second)
-	return &WorkspaceSubroutine{client: client, limiter: exp}
+	return &WorkspaceSubroutine{client: client, accountTypes: accountTypes, limiter: exp}
 }
 
 func (r *WorkspaceSubroutine) GetName() string {
@@ -41,6 +52,28 @@ func (r *WorkspaceSubroutine) GetName() string {
 func (r *WorkspaceSubroutine) Finalize(ctx context.Context, ro runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
 	instance := ro.(*corev1alpha1.Account)
 	cn := MustGetClusteredName(ctx, ro)
+	cfg, _ := commonconfig.LoadConfigFromContext(ctx).(config.OperatorConfig)
+
+	var disabled []string
+	if cfg.Trash.RetentionPeriod <= 0 {
+		disabled = append(disabled, TrashSubroutineFinalizer)
+	}
+	if !cfg.Subroutines.Extension.Enabled {
+		// without the extension subroutine extension objects are removed together with the workspace
+		disabled = append(disabled, ExtensionSubroutineFinalizer)
+	}
+	if err := releaseFinalizers(ctx, r.client, instance, disabled...); err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+	}
+
+	// Extension objects live inside the workspace and are rendered from the AccountInfo stored there,
+	// so the workspace is kept until the extension finalizer is gone. Deleted accounts keep their workspace
+	// until the retention period of the trash expired.
+	if controllerutil.ContainsFinalizer(instance, ExtensionSubroutineFinalizer) ||
+		controllerutil.ContainsFinalizer(instance, TrashSubroutineFinalizer) {
+		next := r.limiter.When(cn)
+		return ctrl.Result{RequeueAfter: next}, nil
+	}
 
 	ws := kcptenancyv1alpha.Workspace{}
 	err := r.client.Get(ctx, client.ObjectKey{Name: instance.Name}, &ws)
@@ -56,6 +89,38 @@ func (r *WorkspaceSubroutine) Finalize(ctx context.Context, ro runtimeobject.Run
 		return ctrl.Result{RequeueAfter: next}, nil
 	}
 
+	if r.accountTypes.WorkspaceDeletionPolicy(instance) == corev1alpha1.WorkspaceDeletionPolicyRetain {
+		// the owner reference is removed before the finalizer, otherwise the garbage collector deletes the workspace
+		original := ws.DeepCopy()
+		ws.SetOwnerReferences(slices.DeleteFunc(ws.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+			return ref.UID == instance.UID
+		}))
+		labels := ws.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[corev1alpha1.OrphanedWorkspaceLabel] = "true"
+		ws.SetLabels(labels)
+		if err := r.client.Patch(ctx, &ws, client.MergeFrom(original)); err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if cfg.Subroutines.Workspace.CascadingDeletion && ws.Spec.Cluster != "" {
+		// child accounts are finalized before their workspace goes away, otherwise their FGA tuples and
+		// extension objects are left behind
+		pending, protected, err := r.deleteChildAccounts(kontext.WithCluster(ctx, logicalcluster.Name(ws.Spec.Cluster)))
+		if err != nil {
+			return ctrl.Result{}, errors.NewOperatorError(err, true, true)
+		}
+		setChildAccountsCondition(instance, pending, protected)
+		if len(pending) > 0 || len(protected) > 0 {
+			next := r.limiter.When(cn)
+			return ctrl.Result{RequeueAfter: next}, nil
+		}
+	}
+
 	err = r.client.Delete(ctx, &ws)
 	if err != nil {
 		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
@@ -74,18 +139,290 @@ func (r *WorkspaceSubroutine) Process(ctx context.Context, runtimeObj runtimeobj
 	instance := runtimeObj.(*corev1alpha1.Account)
 	cfg := commonconfig.LoadConfigFromContext(ctx).(config.OperatorConfig)
 
+	defaultLocation, err := workspaceLocation(cfg.Kcp.WorkspaceShardSelector)
+	if err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(err, false, true)
+	}
+
+	typeName, typePath := r.accountTypes.WorkspaceType(instance.Spec.Type, cfg.Kcp.ProviderWorkspace)
+	workspaceType := kcptenancyv1alpha.WorkspaceTypeReference{
+		Name: kcptenancyv1alpha.WorkspaceTypeName(typeName),
+		Path: typePath,
+	}
+
+	labelPrefixes := splitList(cfg.Subroutines.Workspace.PropagatedLabelPrefixes)
+	annotationPrefixes := splitList(cfg.Subroutines.Workspace.PropagatedAnnotationPrefixes)
+
 	// Test if namespace was already created based on status
+	var placementErr, conflictErr error
 	createdWorkspace := &kcptenancyv1alpha.Workspace{ObjectMeta: metav1.ObjectMeta{Name: instance.Name}}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.client, createdWorkspace, func() error {
-		createdWorkspace.Spec.Type = kcptenancyv1alpha.WorkspaceTypeReference{
-			Name: kcptenancyv1alpha.WorkspaceTypeName(instance.Spec.Type),
-			Path: cfg.Kcp.ProviderWorkspace,
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, createdWorkspace, func() error {
+		if !createdWorkspace.CreationTimestamp.IsZero() {
+			conflictErr = adoptionConflict(instance, createdWorkspace, workspaceType)
+			if conflictErr != nil {
+				return conflictErr
+			}
+			// the opt-in is used up, a workspace retained again later has to be annotated again
+			delete(createdWorkspace.Labels, corev1alpha1.OrphanedWorkspaceLabel)
+			delete(createdWorkspace.Annotations, corev1alpha1.AdoptAnnotation)
+			delete(createdWorkspace.Annotations, corev1alpha1.RestoredAccountAnnotation)
+		} else {
+			// placement is checked before the workspace is created, admission may not have seen the parent AccountInfo
+			parent, err := r.retrieveParentAccountInfo(ctx)
+			if err != nil {
+				return err
+			}
+			placementErr = r.accountTypes.ValidatePlacement(instance.Spec.Type, parent)
+			if placementErr != nil {
+				return placementErr
+			}
+
+			// the shard of a workspace is chosen when it is scheduled, the location of existing workspaces is kept
+			if location := r.accountTypes.WorkspaceLocation(instance, defaultLocation); location != nil {
+				createdWorkspace.Spec.Location = &kcptenancyv1alpha.WorkspaceLocation{Selector: location.Selector.DeepCopy()}
+			}
+			// the type of a workspace is immutable, adopted workspaces keep theirs
+			createdWorkspace.Spec.Type = workspaceType
 		}
 
+		createdWorkspace.Labels = propagateMetadata(instance.Labels, createdWorkspace.Labels, labelPrefixes)
+		createdWorkspace.Annotations = propagateMetadata(instance.Annotations, createdWorkspace.Annotations, annotationPrefixes)
 		return controllerutil.SetOwnerReference(instance, createdWorkspace, r.client.Scheme())
 	})
+	if placementErr != nil {
+		return ctrl.Result{}, errors.NewOperatorError(placementErr, false, false)
+	}
+	if conflictErr != nil {
+		// retried, so the workspace is adopted once it is annotated or the conflicting workspace is gone
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:    corev1alpha1.ConditionWorkspaceReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  corev1alpha1.WorkspaceReasonConflict,
+			Message: conflictErr.Error(),
+		})
+		return ctrl.Result{}, errors.NewOperatorError(conflictErr, true, false)
+	}
 	if err != nil {
 		return ctrl.Result{}, errors.NewOperatorError(err, true, true)
 	}
+
+	setWorkspaceStatus(instance, createdWorkspace, cfg.Subroutines.Workspace.ProvisioningTimeout, time.Now())
 	return ctrl.Result{}, nil
 }
+
+// setWorkspaceStatus mirrors the workspace into the account status. A workspace that is not ready after the
+// provisioning timeout marks the account as failed, the phase is cleared again once the workspace becomes ready.
+func setWorkspaceStatus(instance *corev1alpha1.Account, ws *kcptenancyv1alpha.Workspace, timeout time.Duration, now time.Time) {
+	phase := string(ws.Status.Phase)
+	instance.Status.Workspace = &corev1alpha1.AccountWorkspaceStatus{
+		Phase:     phase,
+		ClusterID: ws.Spec.Cluster,
+		URL:       ws.Spec.URL,
+	}
+	if phase == "" {
+		phase = "Pending"
+	}
+
+	condition := metav1.Condition{
+		Type:    corev1alpha1.ConditionWorkspaceReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  corev1alpha1.WorkspaceReasonReady,
+		Message: "The workspace is ready",
+	}
+	failed := provisioningTimedOut(ws, timeout, now)
+	switch {
+	case ws.Status.Phase == kcpcorev1alpha.LogicalClusterPhaseReady:
+	case failed:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = corev1alpha1.WorkspaceReasonProvisioningTimeout
+		condition.Message = fmt.Sprintf("The workspace is still in phase %s and did not become ready within %s", phase, timeout)
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = corev1alpha1.WorkspaceReasonProvisioning
+		condition.Message = fmt.Sprintf("The workspace is in phase %s", phase)
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+
+	switch {
+	case failed && instance.Status.Phase == "":
+		instance.Status.Phase = corev1alpha1.AccountPhaseFailed
+	case !failed && instance.Status.Phase == corev1alpha1.AccountPhaseFailed:
+		instance.Status.Phase = ""
+	}
+}
+
+// provisioningTimedOut reports whether the workspace did not become ready within the provisioning timeout, a timeout of
+// 0 disables it.
+func provisioningTimedOut(ws *kcptenancyv1alpha.Workspace, timeout time.Duration, now time.Time) bool {
+	return ws.Status.Phase != kcpcorev1alpha.LogicalClusterPhaseReady && timeout > 0 && !ws.CreationTimestamp.IsZero() &&
+		now.Sub(ws.CreationTimestamp.Time) > timeout
+}
+
+// adoptionConflict decides whether the account may take over an existing workspace. Only the workspace of the account
+// and workspaces annotated for the account are adopted, which is how a restored account gets its workspace back. All
+// other workspaces are conflicts.
+func adoptionConflict(instance *corev1alpha1.Account, ws *kcptenancyv1alpha.Workspace, workspaceType kcptenancyv1alpha.WorkspaceTypeReference) error {
+	for _, ref := range ws.GetOwnerReferences() {
+		if ref.UID == instance.UID {
+			return nil
+		}
+	}
+	if ws.GetAnnotations()[corev1alpha1.AdoptAnnotation] == instance.Name {
+		return nil
+	}
+
+	hint := fmt.Sprintf("annotate it with %s=%s to adopt it", corev1alpha1.AdoptAnnotation, instance.Name)
+	if ws.GetLabels()[corev1alpha1.OrphanedWorkspaceLabel] == "true" {
+		return fmt.Errorf("the workspace %q was retained after the deletion of its account, %s", ws.Name, hint)
+	}
+	for _, ref := range ws.GetOwnerReferences() {
+		if ref.Kind == "Account" && strings.HasPrefix(ref.APIVersion, corev1alpha1.GroupVersion.Group+"/") {
+			return fmt.Errorf("the workspace %q belongs to the account with UID %s, %s", ws.Name, ref.UID, hint)
+		}
+	}
+	if ws.Spec.Type.Name != workspaceType.Name || (ws.Spec.Type.Path != "" && ws.Spec.Type.Path != workspaceType.Path) {
+		return fmt.Errorf("the workspace %q already exists with type %s instead of %s, %s",
+			ws.Name, workspaceTypeString(ws.Spec.Type), workspaceTypeString(workspaceType), hint)
+	}
+	return fmt.Errorf("the workspace %q already exists and is not owned by the account, %s", ws.Name, hint)
+}
+
+// propagateMetadata copies the keys with one of the prefixes from the labels or annotations of the account to those of
+// the workspace. Keys with one of the prefixes that the account does not have are removed from the workspace.
+func propagateMetadata(from, to map[string]string, prefixes []string) map[string]string {
+	hasPrefix := func(key string) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) })
+	}
+
+	for key := range to {
+		if _, ok := from[key]; !ok && hasPrefix(key) {
+			delete(to, key)
+		}
+	}
+	for key, value := range from {
+		if hasPrefix(key) {
+			if to == nil {
+				to = map[string]string{}
+			}
+			to[key] = value
+		}
+	}
+	return to
+}
+
+// splitList splits a comma separated setting, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func workspaceTypeString(ref kcptenancyv1alpha.WorkspaceTypeReference) string {
+	return logicalcluster.NewPath(ref.Path).Join(string(ref.Name)).String()
+}
+
+// workspaceLocation parses the configured shard selector, an empty selector leaves the scheduling to kcp
+func workspaceLocation(shardSelector string) (*corev1alpha1.WorkspaceLocation, error) {
+	if shardSelector == "" {
+		return nil, nil
+	}
+	selector, err := metav1.ParseToLabelSelector(shardSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace shard selector %q: %w", shardSelector, err)
+	}
+	return &corev1alpha1.WorkspaceLocation{Selector: selector}, nil
+}
+
+// deleteChildAccounts deletes the accounts in the workspace of the context. It returns the names of those that still
+// exist and, separately, of those whose deletion is forbidden, like accounts with deletion protection. Child accounts
+// in the trash are finalized right away, their workspaces go away with the workspace of the parent anyway.
+func (r *WorkspaceSubroutine) deleteChildAccounts(ctx context.Context) ([]string, []string, error) {
+	accounts := &corev1alpha1.AccountList{}
+	if err := r.client.List(ctx, accounts); err != nil {
+		return nil, nil, err
+	}
+
+	pending := make([]string, 0, len(accounts.Items))
+	var protected []string
+	for i := range accounts.Items {
+		account := &accounts.Items[i]
+		if account.GetDeletionTimestamp() == nil {
+			err := r.client.Delete(ctx, account)
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			if kerrors.IsForbidden(err) {
+				protected = append(protected, account.Name)
+				continue
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("deleting child account %q: %w", account.Name, err)
+			}
+		}
+		if inTrash(account) {
+			original := account.DeepCopy()
+			controllerutil.RemoveFinalizer(account, TrashSubroutineFinalizer)
+			if err := r.client.Patch(ctx, account, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+				return nil, nil, fmt.Errorf("finalizing deleted child account %q: %w", account.Name, err)
+			}
+		}
+		pending = append(pending, account.Name)
+	}
+	slices.Sort(pending)
+	slices.Sort(protected)
+	return pending, protected, nil
+}
+
+func setChildAccountsCondition(instance *corev1alpha1.Account, pending, protected []string) {
+	condition := metav1.Condition{
+		Type:    corev1alpha1.ConditionChildAccountsDeleted,
+		Status:  metav1.ConditionTrue,
+		Reason:  corev1alpha1.ChildAccountsReasonDeleted,
+		Message: "All child accounts are deleted",
+	}
+	switch {
+	case len(protected) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = corev1alpha1.ChildAccountsReasonProtected
+		condition.Message = fmt.Sprintf("The child accounts %s can not be deleted, they are protected from deletion", childAccountNames(protected))
+		if len(pending) > 0 {
+			condition.Message = fmt.Sprintf("%s. Waiting for the deletion of the child accounts %s", condition.Message, childAccountNames(pending))
+		}
+	case len(pending) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = corev1alpha1.ChildAccountsReasonPending
+		condition.Message = fmt.Sprintf("Waiting for the deletion of the child accounts %s", childAccountNames(pending))
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+}
+
+// childAccountNames joins the names of child accounts for a condition message, at most maxReportedChildAccounts are listed
+func childAccountNames(names []string) string {
+	listed := names
+	if len(listed) > maxReportedChildAccounts {
+		listed = listed[:maxReportedChildAccounts]
+	}
+	message := strings.Join(listed, ", ")
+	if len(names) > len(listed) {
+		message = fmt.Sprintf("%s and %d more", message, len(names)-len(listed))
+	}
+	return message
+}
+
+// retrieveParentAccountInfo returns the AccountInfo of the workspace the account resource lives in, nil if that
+// workspace does not belong to an account.
+func (r *WorkspaceSubroutine) retrieveParentAccountInfo(ctx context.Context) (*corev1alpha1.AccountInfo, error) {
+	accountInfo := &corev1alpha1.AccountInfo{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: DefaultAccountInfoName}, accountInfo)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo, nil
+}
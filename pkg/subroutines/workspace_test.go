@@ -3,6 +3,7 @@ package subroutines_test
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,7 +51,7 @@ func (suite *WorkspaceSubroutineTestSuite) SetupTest() {
 	suite.clientMock = new(mocks.Client)
 
 	// Initialize Tested Object(s)
-	suite.testObj = subroutines.NewWorkspaceSubroutine(suite.clientMock)
+	suite.testObj = subroutines.NewWorkspaceSubroutine(suite.clientMock, nil)
 
 	utilruntime.Must(corev1alpha1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme.Scheme))
@@ -137,6 +139,298 @@ func (suite *WorkspaceSubroutineTestSuite) TestFinalize_OK_Workspace_Existing()
 	suite.clientMock.AssertExpectations(suite.T())
 }
 
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Retains_Workspace() {
+	// Given
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", UID: "team-uid"},
+		Spec:       corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount, WorkspaceDeletionPolicy: corev1alpha1.WorkspaceDeletionPolicyRetain},
+	}
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Run(func(_ context.Context, key types.NamespacedName, obj client.Object, _ ...client.GetOption) {
+			obj.SetName(key.Name)
+			obj.SetLabels(map[string]string{"tenant": "regulated"})
+			obj.SetOwnerReferences([]metav1.OwnerReference{{Name: "team", UID: "team-uid"}, {Name: "other", UID: "other-uid"}})
+		}).
+		Return(nil)
+	var patched *kcptenancyv1alpha.Workspace
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace"), mock.Anything).
+		Run(func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) {
+			patched = obj.(*kcptenancyv1alpha.Workspace)
+		}).
+		Return(nil)
+
+	// When
+	// child accounts are kept with the workspace, even with cascading deletion
+	res, err := suite.testObj.Finalize(kontext.WithCluster(suite.cascadingContext(), "some-cluster-id"), testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Require().NotNil(patched)
+	suite.Equal(map[string]string{"tenant": "regulated", corev1alpha1.OrphanedWorkspaceLabel: "true"}, patched.Labels)
+	suite.Equal([]metav1.OwnerReference{{Name: "other", UID: "other-uid"}}, patched.OwnerReferences)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Retain_Patch_Error() {
+	// Given
+	accountTypes, err := corev1alpha1.NewAccountTypeRegistry([]corev1alpha1.AccountTypeDefinition{
+		{Name: corev1alpha1.AccountTypeOrg, OwnsStore: true, WorkspaceDeletionPolicy: corev1alpha1.WorkspaceDeletionPolicyRetain},
+	})
+	suite.Require().NoError(err)
+	testObj := subroutines.NewWorkspaceSubroutine(suite.clientMock, accountTypes)
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "")
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace"), mock.Anything).
+		Return(assert.AnError)
+
+	// When
+	_, opErr := testObj.Finalize(kontext.WithCluster(context.Background(), "some-cluster-id"), testAccount)
+
+	// Then
+	suite.Require().NotNil(opErr)
+	suite.True(opErr.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Waits_For_Extension_Finalizer() {
+	// Given
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{subroutines.WorkspaceSubroutineFinalizer, subroutines.ExtensionSubroutineFinalizer},
+		},
+	}
+	cfg := config.OperatorConfig{}
+	cfg.Subroutines.Extension.Enabled = true
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+	ctx = kontext.WithCluster(ctx, "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Assert().NotZero(res.RequeueAfter)
+	suite.Nil(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Releases_Extension_Finalizer_When_Extensions_Disabled() {
+	// Given
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{subroutines.WorkspaceSubroutineFinalizer, subroutines.ExtensionSubroutineFinalizer},
+		},
+	}
+	suite.clientMock.EXPECT().Patch(mock.Anything, testAccount, mock.Anything).Return(nil)
+	mockGetWorkspaceCallNotFound(suite)
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Equal([]string{subroutines.WorkspaceSubroutineFinalizer}, testAccount.GetFinalizers())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Waits_For_Trash_Finalizer() {
+	// Given
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{subroutines.WorkspaceSubroutineFinalizer, subroutines.TrashSubroutineFinalizer},
+		},
+	}
+	cfg := config.OperatorConfig{}
+	cfg.Trash.RetentionPeriod = time.Hour
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+	ctx = kontext.WithCluster(ctx, "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Assert().NotZero(res.RequeueAfter)
+	suite.Nil(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Releases_Trash_Finalizer_When_Trash_Disabled() {
+	// Given
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{subroutines.WorkspaceSubroutineFinalizer, subroutines.TrashSubroutineFinalizer},
+		},
+	}
+	suite.clientMock.EXPECT().Patch(mock.Anything, testAccount, mock.Anything).Return(nil)
+	mockGetWorkspaceCallNotFound(suite)
+	ctx := kontext.WithCluster(suite.context, "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Zero(res.RequeueAfter)
+	suite.Equal([]string{subroutines.WorkspaceSubroutineFinalizer}, testAccount.GetFinalizers())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Cascading_Waits_For_Child_Accounts() {
+	// Given
+	testAccount := &corev1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "test-account"}}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "https://example.com/")
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Run(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) {
+			cluster, _ := kontext.ClusterFrom(ctx)
+			suite.Equal("some-cluster-id-test-account", cluster.String())
+			list.(*corev1alpha1.AccountList).Items = []corev1alpha1.Account{
+				{ObjectMeta: metav1.ObjectMeta{Name: "child-b"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "child-a", DeletionTimestamp: &metav1.Time{}}},
+			}
+		}).
+		Return(nil)
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.MatchedBy(func(obj client.Object) bool { return obj.GetName() == "child-b" })).
+		Return(nil)
+	ctx := kontext.WithCluster(suite.cascadingContext(), "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Assert().NotZero(res.RequeueAfter)
+	condition := meta.FindStatusCondition(testAccount.Status.Conditions, corev1alpha1.ConditionChildAccountsDeleted)
+	suite.Require().NotNil(condition)
+	suite.Equal(metav1.ConditionFalse, condition.Status)
+	suite.Equal(corev1alpha1.ChildAccountsReasonPending, condition.Reason)
+	suite.Equal("Waiting for the deletion of the child accounts child-a, child-b", condition.Message)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Cascading_Finalizes_Child_Accounts_In_Trash() {
+	// Given
+	testAccount := &corev1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "test-account"}}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "https://example.com/")
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Run(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) {
+			list.(*corev1alpha1.AccountList).Items = []corev1alpha1.Account{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "child-a", DeletionTimestamp: &metav1.Time{},
+					Finalizers: []string{subroutines.WorkspaceSubroutineFinalizer, subroutines.TrashSubroutineFinalizer},
+				},
+				Status: corev1alpha1.AccountStatus{Phase: corev1alpha1.AccountPhaseDeleted, RestorableUntil: &metav1.Time{Time: time.Now().Add(time.Hour)}},
+			}}
+		}).
+		Return(nil)
+	suite.clientMock.EXPECT().
+		Patch(mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+			return obj.GetName() == "child-a" && slices.Equal(obj.GetFinalizers(), []string{subroutines.WorkspaceSubroutineFinalizer})
+		}), mock.Anything).
+		Return(nil)
+	ctx := kontext.WithCluster(suite.cascadingContext(), "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Assert().NotZero(res.RequeueAfter)
+	suite.False(meta.IsStatusConditionTrue(testAccount.Status.Conditions, corev1alpha1.ConditionChildAccountsDeleted))
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Cascading_Reports_Protected_Child_Accounts() {
+	// Given
+	testAccount := &corev1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "test-account"}}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "https://example.com/")
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Run(func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) {
+			list.(*corev1alpha1.AccountList).Items = []corev1alpha1.Account{
+				{ObjectMeta: metav1.ObjectMeta{Name: "child-b"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "child-a"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "child-c", DeletionTimestamp: &metav1.Time{}}},
+			}
+		}).
+		Return(nil)
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.AnythingOfType("*v1alpha1.Account")).
+		RunAndReturn(func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+			return kerrors.NewForbidden(corev1alpha1.GroupVersion.WithResource("accounts").GroupResource(), obj.GetName(),
+				fmt.Errorf("the account is protected from deletion"))
+		})
+	ctx := kontext.WithCluster(suite.cascadingContext(), "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Assert().NotZero(res.RequeueAfter)
+	condition := meta.FindStatusCondition(testAccount.Status.Conditions, corev1alpha1.ConditionChildAccountsDeleted)
+	suite.Require().NotNil(condition)
+	suite.Equal(metav1.ConditionFalse, condition.Status)
+	suite.Equal(corev1alpha1.ChildAccountsReasonProtected, condition.Reason)
+	suite.Equal("The child accounts child-a, child-b can not be deleted, they are protected from deletion. "+
+		"Waiting for the deletion of the child accounts child-c", condition.Message)
+	suite.clientMock.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace"))
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Cascading_Deletes_Workspace() {
+	// Given
+	testAccount := &corev1alpha1.Account{}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "https://example.com/")
+	suite.clientMock.EXPECT().List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).Return(nil)
+	suite.clientMock.EXPECT().
+		Delete(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(nil)
+	ctx := kontext.WithCluster(suite.cascadingContext(), "some-cluster-id")
+
+	// When
+	res, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.Assert().NotZero(res.RequeueAfter)
+	suite.True(meta.IsStatusConditionTrue(testAccount.Status.Conditions, corev1alpha1.ConditionChildAccountsDeleted))
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Cascading_List_Error() {
+	// Given
+	testAccount := &corev1alpha1.Account{}
+	mockGetWorkspaceByName(suite.clientMock, kcpcorev1alpha1.LogicalClusterPhaseReady, "https://example.com/")
+	suite.clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccountList")).
+		Return(kerrors.NewInternalError(fmt.Errorf("failed")))
+	ctx := kontext.WithCluster(suite.cascadingContext(), "some-cluster-id")
+
+	// When
+	_, err := suite.testObj.Finalize(ctx, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) cascadingContext() context.Context {
+	cfg := config.OperatorConfig{}
+	cfg.Subroutines.Workspace.CascadingDeletion = true
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+	return ctx
+}
+
 func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Error_On_Deletion() {
 	// Given
 	testAccount := &corev1alpha1.Account{}
@@ -174,7 +468,7 @@ func (suite *WorkspaceSubroutineTestSuite) TestFinalize_Error_On_Get() {
 
 func (suite *WorkspaceSubroutineTestSuite) TestProcessing_OK() {
 	// Given
-	testAccount := &corev1alpha1.Account{}
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}}
 	suite.clientMock.On("Scheme").Return(scheme.Scheme)
 	mockGetWorkspaceCallNotFound(suite)
 	mockNewWorkspaceCreateCall(suite, defaultExpectedTestNamespace)
@@ -205,7 +499,7 @@ func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Error_On_Get() {
 
 func (suite *WorkspaceSubroutineTestSuite) TestProcessing_CreateError() {
 	// Given
-	testAccount := &corev1alpha1.Account{}
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}}
 	suite.clientMock.On("Scheme").Return(scheme.Scheme)
 	mockGetWorkspaceCallNotFound(suite)
 	suite.clientMock.EXPECT().
@@ -222,6 +516,453 @@ func (suite *WorkspaceSubroutineTestSuite) TestProcessing_CreateError() {
 	suite.clientMock.AssertExpectations(suite.T())
 }
 
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_OK_Account_In_Org() {
+	// Given
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount}}
+	suite.clientMock.On("Scheme").Return(scheme.Scheme)
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	mockGetParentAccountInfo(suite, corev1alpha1.AccountTypeOrg)
+	mockNewWorkspaceCreateCall(suite, defaultExpectedTestNamespace)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(err)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Org_In_Account_Workspace() {
+	// Given
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}}
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	mockGetParentAccountInfo(suite, corev1alpha1.AccountTypeAccount)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.False(err.Sentry())
+	suite.ErrorContains(err.Err(), "an org can not be created inside the account workspace")
+	suite.clientMock.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Account_Outside_Org() {
+	// Given
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount}}
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_AccountInfo_Error() {
+	// Given
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount}}
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Return(kerrors.NewInternalError(fmt.Errorf("failed")))
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.True(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Registered_Type() {
+	// Given
+	accountTypes, err := corev1alpha1.NewAccountTypeRegistry([]corev1alpha1.AccountTypeDefinition{
+		{Name: corev1alpha1.AccountTypeOrg, OwnsStore: true},
+		{Name: "project", AllowedParents: []corev1alpha1.AccountType{corev1alpha1.AccountTypeOrg}, WorkspaceType: corev1alpha1.WorkspaceTypeReference{Name: "project-ws", Path: "root:types"}},
+	})
+	suite.Require().NoError(err)
+	testObj := subroutines.NewWorkspaceSubroutine(suite.clientMock, accountTypes)
+
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: "project"}}
+	suite.clientMock.On("Scheme").Return(scheme.Scheme)
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	mockGetParentAccountInfo(suite, corev1alpha1.AccountTypeOrg)
+	suite.clientMock.EXPECT().
+		Create(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
+			workspace := obj.(*kcptenancyv1alpha.Workspace)
+			suite.Equal(kcptenancyv1alpha.WorkspaceTypeName("project-ws"), workspace.Spec.Type.Name)
+			suite.Equal("root:types", workspace.Spec.Type.Path)
+		}).
+		Return(nil)
+
+	// When
+	_, opErr := testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Nil(opErr)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Workspace_Location() {
+	typeLocation := &corev1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "orgs"}}}
+	accountLocation := &corev1alpha1.WorkspaceLocation{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "dedicated"}}}
+	accountTypes, err := corev1alpha1.NewAccountTypeRegistry([]corev1alpha1.AccountTypeDefinition{
+		{Name: corev1alpha1.AccountTypeOrg, OwnsStore: true, WorkspaceLocation: typeLocation},
+		{Name: corev1alpha1.AccountTypeAccount, AllowedParents: []corev1alpha1.AccountType{corev1alpha1.AccountTypeOrg}},
+	})
+	suite.Require().NoError(err)
+
+	tests := []struct {
+		name          string
+		account       corev1alpha1.AccountSpec
+		shardSelector string
+		expected      map[string]string
+	}{
+		{name: "no location", account: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount}},
+		{name: "configured location", account: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount}, shardSelector: "shard=default", expected: map[string]string{"shard": "default"}},
+		{name: "location of the type", account: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}, shardSelector: "shard=default", expected: map[string]string{"shard": "orgs"}},
+		{name: "location of the account", account: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg, WorkspaceLocation: accountLocation}, shardSelector: "shard=default", expected: map[string]string{"shard": "dedicated"}},
+	}
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			clientMock := new(mocks.Client)
+			testObj := subroutines.NewWorkspaceSubroutine(clientMock, accountTypes)
+			cfg := config.OperatorConfig{}
+			cfg.Kcp.WorkspaceShardSelector = test.shardSelector
+			ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+			clientMock.On("Scheme").Return(scheme.Scheme)
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+				Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+			parentType := corev1alpha1.AccountTypeOrg
+			if test.account.Type == corev1alpha1.AccountTypeOrg {
+				clientMock.EXPECT().
+					Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+					Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+			} else {
+				clientMock.EXPECT().
+					Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+					Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+						obj.(*corev1alpha1.AccountInfo).Spec.Account = corev1alpha1.AccountLocation{Name: "parent", Type: parentType}
+					}).
+					Return(nil)
+			}
+			var created *kcptenancyv1alpha.Workspace
+			clientMock.EXPECT().
+				Create(mock.Anything, mock.Anything).
+				Run(func(ctx context.Context, obj client.Object, opts ...client.CreateOption) {
+					created = obj.(*kcptenancyv1alpha.Workspace)
+				}).
+				Return(nil)
+
+			_, opErr := testObj.Process(ctx, &corev1alpha1.Account{Spec: test.account})
+
+			suite.Nil(opErr)
+			suite.Require().NotNil(created)
+			if test.expected == nil {
+				suite.Nil(created.Spec.Location)
+				return
+			}
+			suite.Require().NotNil(created.Spec.Location)
+			suite.Equal(test.expected, created.Spec.Location.Selector.MatchLabels)
+		})
+	}
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Invalid_Shard_Selector() {
+	// Given
+	cfg := config.OperatorConfig{}
+	cfg.Kcp.WorkspaceShardSelector = "shard in (a"
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+	// When
+	_, err := suite.testObj.Process(ctx, &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeOrg}})
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Workspace_Status() {
+	tests := []struct {
+		name           string
+		phase          kcpcorev1alpha1.LogicalClusterPhaseType
+		age            time.Duration
+		accountPhase   corev1alpha1.AccountPhase
+		noTimeout      bool
+		expectedReason string
+		expectedPhase  corev1alpha1.AccountPhase
+	}{
+		{name: "ready", phase: kcpcorev1alpha1.LogicalClusterPhaseReady, age: time.Hour, expectedReason: corev1alpha1.WorkspaceReasonReady},
+		{name: "ready again", phase: kcpcorev1alpha1.LogicalClusterPhaseReady, age: time.Hour, accountPhase: corev1alpha1.AccountPhaseFailed, expectedReason: corev1alpha1.WorkspaceReasonReady},
+		{name: "initializing", phase: kcpcorev1alpha1.LogicalClusterPhaseInitializing, age: time.Minute, expectedReason: corev1alpha1.WorkspaceReasonProvisioning},
+		{name: "timed out", phase: kcpcorev1alpha1.LogicalClusterPhaseInitializing, age: time.Hour, expectedReason: corev1alpha1.WorkspaceReasonProvisioningTimeout, expectedPhase: corev1alpha1.AccountPhaseFailed},
+		{name: "timed out while suspended", phase: kcpcorev1alpha1.LogicalClusterPhaseScheduling, age: time.Hour, accountPhase: corev1alpha1.AccountPhaseSuspended, expectedReason: corev1alpha1.WorkspaceReasonProvisioningTimeout, expectedPhase: corev1alpha1.AccountPhaseSuspended},
+		{name: "timeout disabled", phase: kcpcorev1alpha1.LogicalClusterPhaseInitializing, age: time.Hour, noTimeout: true, expectedReason: corev1alpha1.WorkspaceReasonProvisioning},
+	}
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			clientMock := new(mocks.Client)
+			testObj := subroutines.NewWorkspaceSubroutine(clientMock, nil)
+			cfg := config.OperatorConfig{}
+			if !test.noTimeout {
+				cfg.Subroutines.Workspace.ProvisioningTimeout = 10 * time.Minute
+			}
+			ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+			testAccount := &corev1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Name: "team", UID: "team-uid"},
+				Spec:       corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount},
+				Status:     corev1alpha1.AccountStatus{Phase: test.accountPhase},
+			}
+			clientMock.On("Scheme").Return(scheme.Scheme)
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+				Run(func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) {
+					ws := obj.(*kcptenancyv1alpha.Workspace)
+					ws.Name = "team"
+					ws.OwnerReferences = []metav1.OwnerReference{{APIVersion: "core.openmfp.org/v1alpha1", Kind: "Account", Name: "team", UID: "team-uid"}}
+					ws.CreationTimestamp = metav1.NewTime(time.Now().Add(-test.age))
+					ws.Spec.Cluster = "team-cluster"
+					ws.Spec.URL = "https://kcp.example.com/clusters/team-cluster"
+					ws.Status.Phase = test.phase
+				}).
+				Return(nil)
+			clientMock.EXPECT().Update(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).Return(nil)
+
+			_, err := testObj.Process(ctx, testAccount)
+
+			suite.Require().Nil(err)
+			suite.Equal(&corev1alpha1.AccountWorkspaceStatus{
+				Phase:     string(test.phase),
+				ClusterID: "team-cluster",
+				URL:       "https://kcp.example.com/clusters/team-cluster",
+			}, testAccount.Status.Workspace)
+			condition := meta.FindStatusCondition(testAccount.Status.Conditions, corev1alpha1.ConditionWorkspaceReady)
+			suite.Require().NotNil(condition)
+			suite.Equal(test.expectedReason, condition.Reason)
+			suite.Equal(test.expectedReason == corev1alpha1.WorkspaceReasonReady, condition.Status == metav1.ConditionTrue)
+			suite.Equal(test.expectedPhase, testAccount.Status.Phase)
+		})
+	}
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Workspace_Adoption() {
+	accountType := kcptenancyv1alpha.WorkspaceTypeReference{Name: "account", Path: "root"}
+	otherType := kcptenancyv1alpha.WorkspaceTypeReference{Name: "universal", Path: "root"}
+	otherAccount := metav1.OwnerReference{APIVersion: "core.openmfp.org/v1alpha1", Kind: "Account", Name: "team", UID: "other-uid"}
+
+	tests := []struct {
+		name        string
+		workspace   kcptenancyv1alpha.Workspace
+		expectAdopt bool
+	}{
+		{
+			name: "unowned workspace of the account type",
+			workspace: kcptenancyv1alpha.Workspace{
+				Spec: kcptenancyv1alpha.WorkspaceSpec{Type: accountType},
+			},
+		},
+		{
+			name: "annotated workspace of the account type",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1alpha1.AdoptAnnotation: "team"}},
+				Spec:       kcptenancyv1alpha.WorkspaceSpec{Type: accountType},
+			},
+			expectAdopt: true,
+		},
+		{
+			name: "unowned workspace of a different type",
+			workspace: kcptenancyv1alpha.Workspace{
+				Spec: kcptenancyv1alpha.WorkspaceSpec{Type: otherType},
+			},
+		},
+		{
+			name: "annotated workspace of a different type",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1alpha1.AdoptAnnotation: "team", corev1alpha1.RestoredAccountAnnotation: "{}"}},
+				Spec:       kcptenancyv1alpha.WorkspaceSpec{Type: otherType},
+			},
+			expectAdopt: true,
+		},
+		{
+			name: "workspace annotated for another account",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1alpha1.AdoptAnnotation: "other"}},
+				Spec:       kcptenancyv1alpha.WorkspaceSpec{Type: otherType},
+			},
+		},
+		{
+			name: "retained workspace",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{corev1alpha1.OrphanedWorkspaceLabel: "true"}},
+				Spec:       kcptenancyv1alpha.WorkspaceSpec{Type: accountType},
+			},
+		},
+		{
+			name: "annotated retained workspace",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{corev1alpha1.OrphanedWorkspaceLabel: "true"},
+					Annotations: map[string]string{corev1alpha1.AdoptAnnotation: "team"},
+				},
+				Spec: kcptenancyv1alpha.WorkspaceSpec{Type: accountType},
+			},
+			expectAdopt: true,
+		},
+		{
+			name: "workspace of another account",
+			workspace: kcptenancyv1alpha.Workspace{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{otherAccount}},
+				Spec:       kcptenancyv1alpha.WorkspaceSpec{Type: accountType},
+			},
+		},
+	}
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			clientMock := new(mocks.Client)
+			testObj := subroutines.NewWorkspaceSubroutine(clientMock, nil)
+			cfg := config.OperatorConfig{}
+			cfg.Kcp.ProviderWorkspace = "root"
+			ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+			testAccount := &corev1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Name: "team", UID: "team-uid"},
+				Spec:       corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount},
+			}
+			clientMock.On("Scheme").Return(scheme.Scheme).Maybe()
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+				Run(func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) {
+					test.workspace.DeepCopyInto(obj.(*kcptenancyv1alpha.Workspace))
+					obj.SetName("team")
+					obj.SetCreationTimestamp(metav1.Now())
+				}).
+				Return(nil)
+			var updated *kcptenancyv1alpha.Workspace
+			if test.expectAdopt {
+				clientMock.EXPECT().
+					Update(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+					Run(func(_ context.Context, obj client.Object, _ ...client.UpdateOption) {
+						updated = obj.(*kcptenancyv1alpha.Workspace)
+					}).
+					Return(nil)
+			}
+
+			_, err := testObj.Process(ctx, testAccount)
+
+			condition := meta.FindStatusCondition(testAccount.Status.Conditions, corev1alpha1.ConditionWorkspaceReady)
+			suite.Require().NotNil(condition)
+			if test.expectAdopt {
+				suite.Require().Nil(err)
+				suite.Require().NotNil(updated)
+				suite.Equal(test.workspace.Spec.Type, updated.Spec.Type)
+				suite.NotContains(updated.Labels, corev1alpha1.OrphanedWorkspaceLabel)
+				suite.NotContains(updated.Annotations, corev1alpha1.AdoptAnnotation)
+				suite.NotContains(updated.Annotations, corev1alpha1.RestoredAccountAnnotation)
+				suite.Require().Len(updated.OwnerReferences, 1)
+				suite.Equal(testAccount.UID, updated.OwnerReferences[0].UID)
+				suite.Equal(corev1alpha1.WorkspaceReasonProvisioning, condition.Reason)
+			} else {
+				suite.Require().NotNil(err)
+				suite.True(err.Retry())
+				suite.ErrorContains(err.Err(), corev1alpha1.AdoptAnnotation+"=team")
+				suite.Equal(corev1alpha1.WorkspaceReasonConflict, condition.Reason)
+				suite.Equal(err.Err().Error(), condition.Message)
+			}
+			clientMock.AssertExpectations(suite.T())
+		})
+	}
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Propagates_Metadata() {
+	// Given
+	cfg := config.OperatorConfig{}
+	cfg.Subroutines.Workspace.PropagatedLabelPrefixes = "cost-center, environment"
+	cfg.Subroutines.Workspace.PropagatedAnnotationPrefixes = "policy.example.com/"
+	ctx, _, _ := openmfpcontext.StartContext(suite.log, cfg, 1*time.Minute)
+
+	testAccount := &corev1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team",
+			UID:         "team-uid",
+			Labels:      map[string]string{"cost-center": "4711", "team": "a"},
+			Annotations: map[string]string{"policy.example.com/tier": "gold", "kcp.io/cluster": "org-ws"},
+		},
+		Spec: corev1alpha1.AccountSpec{Type: corev1alpha1.AccountTypeAccount},
+	}
+	suite.clientMock.On("Scheme").Return(scheme.Scheme)
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Run(func(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) {
+			ws := obj.(*kcptenancyv1alpha.Workspace)
+			ws.Name = "team"
+			ws.CreationTimestamp = metav1.Now()
+			ws.OwnerReferences = []metav1.OwnerReference{{APIVersion: "core.openmfp.org/v1alpha1", Kind: "Account", Name: "team", UID: "team-uid"}}
+			ws.Labels = map[string]string{"cost-center": "0815", "environment": "dev", "shard": "a"}
+			ws.Annotations = map[string]string{"policy.example.com/legacy": "true", "kcp.io/cluster": "team-ws"}
+		}).
+		Return(nil)
+	var updated *kcptenancyv1alpha.Workspace
+	suite.clientMock.EXPECT().
+		Update(mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Run(func(_ context.Context, obj client.Object, _ ...client.UpdateOption) {
+			updated = obj.(*kcptenancyv1alpha.Workspace)
+		}).
+		Return(nil)
+
+	// When
+	_, err := suite.testObj.Process(ctx, testAccount)
+
+	// Then
+	suite.Require().Nil(err)
+	suite.Require().NotNil(updated)
+	suite.Equal(map[string]string{"cost-center": "4711", "shard": "a"}, updated.Labels)
+	suite.Equal(map[string]string{"policy.example.com/tier": "gold", "kcp.io/cluster": "team-ws"}, updated.Annotations)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceSubroutineTestSuite) TestProcessing_Unknown_Type() {
+	// Given
+	testAccount := &corev1alpha1.Account{Spec: corev1alpha1.AccountSpec{Type: "project"}}
+	suite.clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.Workspace")).
+		Return(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	mockGetParentAccountInfo(suite, corev1alpha1.AccountTypeOrg)
+
+	// When
+	_, err := suite.testObj.Process(suite.context, testAccount)
+
+	// Then
+	suite.Require().NotNil(err)
+	suite.False(err.Retry())
+	suite.ErrorContains(err.Err(), `the account type "project" is not defined`)
+	suite.clientMock.AssertExpectations(suite.T())
+}
+
 func TestWorkspaceSubroutineTestSuite(t *testing.T) {
 	suite.Run(t, new(WorkspaceSubroutineTestSuite))
 }
@@ -273,3 +1014,14 @@ func mockDeleteWorkspaceCallFailed(suite *WorkspaceSubroutineTestSuite) *mocks.C
 		Delete(mock.Anything, mock.Anything).
 		Return(kerrors.NewInternalError(fmt.Errorf("failed")))
 }
+
+func mockGetParentAccountInfo(suite *WorkspaceSubroutineTestSuite, accountType corev1alpha1.AccountType) *mocks.Client_Get_Call {
+	return suite.clientMock.EXPECT().
+		Get(mock.Anything, types.NamespacedName{Name: subroutines.DefaultAccountInfoName}, mock.AnythingOfType("*v1alpha1.AccountInfo")).
+		Run(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) {
+			actual, _ := obj.(*corev1alpha1.AccountInfo)
+			actual.Name = key.Name
+			actual.Spec.Account = corev1alpha1.AccountLocation{Name: "parent", Type: accountType, Path: "root:orgs:parent"}
+		}).
+		Return(nil)
+}
@@ -0,0 +1,160 @@
+package templating
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// FuncMap returns the functions that are available in extension templates. The functions are free of side effects,
+// they do not access the environment, the filesystem or the network.
+//
+// Strings:  lower, upper, trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, split, join, quote,
+// truncate, dnsLabel
+// Hashes:   sha256, shortHash
+// Defaults: default, coalesce, empty, required
+// JSON:     toJson, fromJson, jsonpath
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"quote":      strconv.Quote,
+		"truncate":   truncate,
+		"dnsLabel":   dnsLabel,
+
+		"sha256":    sha256Sum,
+		"shortHash": shortHash,
+
+		"default":  defaultValue,
+		"coalesce": coalesce,
+		"empty":    empty,
+		"required": required,
+
+		"toJson":   toJSON,
+		"fromJson": fromJSON,
+		"jsonpath": jsonPath,
+	}
+}
+
+func join(sep string, list any) (string, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join expects a list, got %T", list)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func truncate(length int, s string) string {
+	if length < 0 || len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
+
+var invalidDNSLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// dnsLabel converts s into a valid RFC 1123 label, as used for most kubernetes object names.
+func dnsLabel(s string) string {
+	label := invalidDNSLabelChars.ReplaceAllString(strings.ToLower(s), "-")
+	label = truncate(63, label)
+	return strings.Trim(label, "-")
+}
+
+func sha256Sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// shortHash returns the first length hex characters of the sha256 sum of s.
+func shortHash(length int, s string) string {
+	return truncate(length, sha256Sum(s))
+}
+
+// empty reports whether the value is missing or the zero value of its type.
+func empty(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// defaultValue returns value unless it is empty, in which case def is returned.
+func defaultValue(def any, value ...any) any {
+	if len(value) == 0 || empty(value[0]) {
+		return def
+	}
+	return value[0]
+}
+
+// coalesce returns the first value that is not empty.
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// required fails the template execution with msg if value is empty.
+func required(msg string, value any) (any, error) {
+	if empty(value) {
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return value, nil
+}
+
+func toJSON(value any) (string, error) {
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+func fromJSON(s string) (any, error) {
+	var out any
+	err := json.Unmarshal([]byte(s), &out)
+	return out, err
+}
+
+// jsonPath evaluates a kubernetes jsonpath expression, e.g. {.spec.displayName}, against value. Missing keys result
+// in an empty string.
+func jsonPath(expression string, value any) (string, error) {
+	jp := jsonpath.New("template").AllowMissingKeys(true)
+	if err := jp.Parse(expression); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,79 @@
+package templating_test
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/account-operator/pkg/templating"
+)
+
+func TestFuncMap(t *testing.T) {
+	data := map[string]any{
+		"name":  "My_Account.Name",
+		"empty": "",
+		"list":  []any{"a", "b"},
+		"obj":   map[string]any{"spec": map[string]any{"type": "org"}},
+	}
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{`{{ lower "ABC" }}`, "abc"},
+		{`{{ upper "abc" }}`, "ABC"},
+		{`{{ trim "  abc  " }}`, "abc"},
+		{`{{ "root:orgs:test" | trimPrefix "root:" }}`, "orgs:test"},
+		{`{{ "test-account" | trimSuffix "-account" }}`, "test"},
+		{`{{ "a.b.c" | replace "." "-" }}`, "a-b-c"},
+		{`{{ "abc" | contains "b" }}`, "true"},
+		{`{{ "abc" | hasPrefix "a" }}`, "true"},
+		{`{{ "abc" | hasSuffix "a" }}`, "false"},
+		{`{{ "a,b" | split "," | join "-" }}`, "a-b"},
+		{`{{ join "," .list }}`, "a,b"},
+		{`{{ quote "a" }}`, `"a"`},
+		{`{{ "abcdef" | truncate 3 }}`, "abc"},
+		{`{{ dnsLabel .name }}`, "my-account-name"},
+		{`{{ sha256 "abc" }}`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`{{ shortHash 8 "abc" }}`, "ba7816bf"},
+		{`{{ .empty | default "fallback" }}`, "fallback"},
+		{`{{ .name | default "fallback" }}`, "My_Account.Name"},
+		{`{{ coalesce .empty "" "second" }}`, "second"},
+		{`{{ empty .empty }}`, "true"},
+		{`{{ empty .list }}`, "false"},
+		{`{{ required "missing" .name }}`, "My_Account.Name"},
+		{`{{ toJson .list }}`, `["a","b"]`},
+		{`{{ (fromJson "{\"a\":\"b\"}").a }}`, "b"},
+		{`{{ jsonpath "{.spec.type}" .obj }}`, "org"},
+		{`{{ jsonpath "{.spec.missing}" .obj }}`, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(templating.FuncMap()).Parse(test.template)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, data))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestFuncMap_Errors(t *testing.T) {
+	for _, text := range []string{
+		`{{ required "name is required" .empty }}`,
+		`{{ join "," .name }}`,
+		`{{ fromJson "{" }}`,
+		`{{ jsonpath "{.spec" .obj }}`,
+	} {
+		t.Run(text, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(templating.FuncMap()).Parse(text)
+			require.NoError(t, err)
+			assert.Error(t, tmpl.Execute(&bytes.Buffer{}, map[string]any{"empty": "", "name": "a", "obj": map[string]any{}}))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package templating renders the go templates of account extensions.
+//
+// Extension templates are json documents. Every string value of the document that contains a template action is
+// executed as a go template, keys and non string values are kept as they are. A string value that consists of a single
+// template action is replaced by the json value the action renders, so "{{ index .Data \"replicas\" }}" can render
+// the number 3 and "{{ toJson .Data.labels }}" an object. Output that is not valid json stays a string, quote keeps
+// the output a string in any case. The templates are executed against a Data value, so a template can reference for
+// example
+//
+//	{{ .Account.metadata.name }}               the name of the account
+//	{{ .Account.spec.displayName }}            any other field of the account, in its json representation
+//	{{ index .Data "costCenter" }}             a value from the account spec.data
+//	{{ .AccountInfo.Organization.Name }}       the organization the account belongs to
+//	{{ .AccountInfo.Account.Path }}            the logical cluster path of the account workspace
+//	{{ .AccountInfo.FGAStoreID }}              the FGA store of the organization
+//	{{ .Config.Kcp.ProviderWorkspace }}        the provider workspace of the operator
+//
+// Referencing a missing map key is an error, optional values can be looked up with index or jsonpath and combined
+// with default. The available functions are listed in FuncMap.
+package templating
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Data is the context extension templates are executed against.
+type Data struct {
+	// Account is the json representation of the account the extension belongs to
+	Account map[string]any
+	// Data is the decoded spec.data of the account, nil if the account does not carry data
+	Data any
+	// AccountInfo is the resolved AccountInfo of the account workspace
+	AccountInfo AccountInfo
+	// Config is the part of the operator configuration that templates may depend on
+	Config Config
+}
+
+// Config is the template view of the operator configuration. It only carries settings that are meant to be used by
+// tenant-authored templates.
+type Config struct {
+	Kcp KcpConfig
+}
+
+// KcpConfig holds the kcp settings of the operator.
+type KcpConfig struct {
+	// ProviderWorkspace is the workspace the operator exports its APIs and workspace types from
+	ProviderWorkspace string
+}
+
+// AccountInfo is the template view of the AccountInfo resource of the account workspace.
+type AccountInfo struct {
+	Account Location
+	// Parent is nil for organizations
+	Parent       *Location
+	Organization Location
+	FGAStoreID   string
+}
+
+// Location describes where the workspace of an account is located.
+type Location struct {
+	Name string
+	Type string
+	// ClusterID is the logical cluster of the workspace generated for the account
+	ClusterID string
+	// OriginClusterID is the logical cluster that holds the account resource
+	OriginClusterID string
+	Path            string
+	URL             string
+	DisplayName     string
+}
+
+// Render decodes the raw json document and executes every string value as a go template against data. Errors are
+// reported as field errors relative to path.
+func Render(path *field.Path, raw []byte, data any) (map[string]any, *field.Error) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, field.Invalid(path, string(raw), err.Error())
+	}
+
+	rendered, err := renderValue(path, doc, func(tmpl *template.Template) (string, error) {
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, data)
+		return buf.String(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	out, _ := rendered.(map[string]any)
+	return out, nil
+}
+
+// Validate parses all templates of the raw json document without executing them.
+func Validate(path *field.Path, raw []byte) *field.Error {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return field.Invalid(path, string(raw), err.Error())
+	}
+
+	_, err := renderValue(path, doc, func(tmpl *template.Template) (string, error) {
+		return "", nil
+	})
+	return err
+}
+
+func renderValue(path *field.Path, value any, execute func(*template.Template) (string, error)) (any, *field.Error) {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			rendered, err := renderValue(path.Child(key), item, execute)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := renderValue(path.Index(i), item, execute)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New(path.String()).Option("missingkey=error").Funcs(FuncMap()).Parse(v)
+		if err != nil {
+			return nil, field.Invalid(path, v, err.Error())
+		}
+		rendered, err := execute(tmpl)
+		if err != nil {
+			return nil, field.Invalid(path, v, err.Error())
+		}
+		if isSingleAction(tmpl) {
+			var decoded any
+			if err := json.Unmarshal([]byte(rendered), &decoded); err == nil {
+				return decoded, nil
+			}
+		}
+		return rendered, nil
+	default:
+		return v, nil
+	}
+}
+
+// isSingleAction reports whether the template consists of nothing but one action, e.g. "{{ .Data.replicas }}".
+func isSingleAction(tmpl *template.Template) bool {
+	nodes := tmpl.Tree.Root.Nodes
+	return len(nodes) == 1 && nodes[0].Type() == parse.NodeAction
+}
@@ -0,0 +1,115 @@
+package templating_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openmfp/account-operator/pkg/templating"
+)
+
+func testData() templating.Data {
+	return templating.Data{
+		Account: map[string]any{
+			"metadata": map[string]any{"name": "test-account"},
+			"spec":     map[string]any{"type": "account", "displayName": "Test Account"},
+		},
+		Data: map[string]any{"costCenter": "cc-1", "tags": []any{"a", "b"}, "replicas": int64(3), "id": "42"},
+		AccountInfo: templating.AccountInfo{
+			Account:      templating.Location{Name: "test-account", Path: "root:orgs:org:test-account"},
+			Organization: templating.Location{Name: "org"},
+			FGAStoreID:   "store-id",
+		},
+		Config: templating.Config{Kcp: templating.KcpConfig{ProviderWorkspace: "root"}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected map[string]any
+		errField string
+	}{
+		{
+			name:     "plain values are kept",
+			template: `{"replicas":2,"enabled":true,"name":"static","list":[1,"two"]}`,
+			expected: map[string]any{"replicas": int64(2), "enabled": true, "name": "static", "list": []any{int64(1), "two"}},
+		},
+		{
+			name:     "account fields",
+			template: `{"name":"{{ .Account.metadata.name }}","display":"{{ .Account.spec.displayName | lower }}"}`,
+			expected: map[string]any{"name": "test-account", "display": "test account"},
+		},
+		{
+			name:     "account info and config",
+			template: `{"path":"{{ .AccountInfo.Account.Path }}","org":"{{ .AccountInfo.Organization.Name }}","store":"{{ .AccountInfo.FGAStoreID }}","ws":"{{ .Config.Kcp.ProviderWorkspace }}"}`,
+			expected: map[string]any{"path": "root:orgs:org:test-account", "org": "org", "store": "store-id", "ws": "root"},
+		},
+		{
+			name:     "nested values and lists",
+			template: `{"labels":{"cost-center":"{{ index .Data \"costCenter\" }}"},"items":["{{ join \",\" (index .Data \"tags\") }}"]}`,
+			expected: map[string]any{"labels": map[string]any{"cost-center": "cc-1"}, "items": []any{"a,b"}},
+		},
+		{
+			name:     "single actions render json values",
+			template: `{"replicas":"{{ index .Data \"replicas\" }}","enabled":"{{ eq .Account.spec.type \"account\" }}","tags":"{{ toJson (index .Data \"tags\") }}"}`,
+			expected: map[string]any{"replicas": int64(3), "enabled": true, "tags": []any{"a", "b"}},
+		},
+		{
+			name:     "other templates and quoted actions render strings",
+			template: `{"replicas":"x{{ index .Data \"replicas\" }}","id":"{{ index .Data \"id\" | quote }}","name":"{{ .Account.metadata.name }}"}`,
+			expected: map[string]any{"replicas": "x3", "id": "42", "name": "test-account"},
+		},
+		{
+			name:     "missing optional value with default",
+			template: `{"tier":"{{ index .Data \"tier\" | default \"basic\" }}"}`,
+			expected: map[string]any{"tier": "basic"},
+		},
+		{
+			name:     "missing key is an error",
+			template: `{"labels":{"owner":"{{ .Account.metadata.owner }}"}}`,
+			errField: "spec.labels.owner",
+		},
+		{
+			name:     "parse error",
+			template: `{"list":["{{ .Account "]}`,
+			errField: "spec.list[0]",
+		},
+		{
+			name:     "required value",
+			template: `{"owner":"{{ required \"owner must be set\" (index .Data \"owner\") }}"}`,
+			errField: "spec.owner",
+		},
+		{
+			name:     "invalid json",
+			template: `{"foo":`,
+			errField: "spec",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := templating.Render(field.NewPath("spec"), []byte(test.template), testData())
+			if test.errField != "" {
+				require.NotNil(t, err)
+				assert.Equal(t, field.ErrorTypeInvalid, err.Type)
+				assert.Equal(t, test.errField, err.Field)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, test.expected, rendered)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, templating.Validate(field.NewPath("spec"), []byte(`{"name":"{{ .Account.metadata.doesNotExist }}"}`)))
+	assert.Nil(t, templating.Validate(field.NewPath("spec"), []byte(`{"name":"{{ index .Data \"a\" | dnsLabel }}"}`)))
+
+	err := templating.Validate(field.NewPath("spec"), []byte(`{"name":"{{ unknownFunc .Account }}"}`))
+	require.NotNil(t, err)
+	assert.Equal(t, "spec.name", err.Field)
+}